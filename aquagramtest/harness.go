@@ -0,0 +1,256 @@
+package aquagramtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+)
+
+/*
+Harness simulates users talking to a bot, dispatching each
+update synchronously so its effects can be inspected right away:
+
+	h := aquagramtest.NewHarness(t)
+	h.Bot.OnCommand("start", StartCommandHandler)
+
+	res := h.User(42).Send("/start")
+	if res.Err != nil || len(res.Messages()) != 1 {
+		t.Fatal("the bot did not reply")
+	}
+*/
+type Harness struct {
+	Server *Server
+	Bot    *aquagram.Bot
+
+	t testing.TB
+}
+
+// NewHarness starts a [Server] and a bot using it, both are closed when the test finishes.
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	harness := new(Harness)
+	harness.t = t
+	harness.Server = NewServer()
+	harness.Bot = harness.Server.NewBot()
+
+	// errors are reported by Result.Err
+	harness.Bot.Config.OnErrorFunc = nil
+
+	t.Cleanup(func() {
+		harness.Bot.Stop()
+		harness.Server.Close()
+	})
+
+	if _, err := harness.Bot.GetMe(); err != nil {
+		t.Fatal(err)
+	}
+
+	return harness
+}
+
+// User returns a user identified by id, with its private chat known by the server.
+func (harness *Harness) User(id int64) *User {
+	user := new(User)
+	user.User = &aquagram.User{
+		ID:        id,
+		FirstName: fmt.Sprintf("User %d", id),
+		Username:  fmt.Sprintf("user%d", id),
+	}
+
+	user.harness = harness
+
+	if harness.Server.Chat(id) == nil {
+		harness.Server.AddChat(user.PrivateChat())
+	}
+
+	return user
+}
+
+// Group returns a supergroup known by the server, with the bot as administrator.
+func (harness *Harness) Group(id int64, title string) *aquagram.Chat {
+	chat := harness.Server.Chat(id)
+	if chat != nil {
+		return chat
+	}
+
+	chat = &aquagram.Chat{
+		ID:    id,
+		Type:  aquagram.ChatTypeSuperGroup,
+		Title: title,
+	}
+
+	harness.Server.AddChat(chat)
+	harness.Server.SetChatMember(id, &aquagram.ChatMember{
+		Status: aquagram.ChatMemberStatusAdministrator,
+		User:   harness.Server.Me,
+	})
+
+	return chat
+}
+
+/*
+Dispatch delivers update to the bot and waits for its handlers.
+
+The returned [Result] holds the requests made by the bot meanwhile.
+*/
+func (harness *Harness) Dispatch(update *aquagram.Update) *Result {
+	harness.t.Helper()
+
+	if update.UpdateID == 0 {
+		update.UpdateID = harness.Server.NextUpdateID()
+	}
+
+	before := len(harness.Server.Requests(aquagram.EmptyString))
+
+	result := new(Result)
+	result.Update = update
+	result.Err = harness.Bot.ProcessUpdate(update)
+	result.Requests = harness.Server.Requests(aquagram.EmptyString)[before:]
+
+	return result
+}
+
+// User is a simulated user of a [Harness].
+type User struct {
+	*aquagram.User
+
+	harness *Harness
+}
+
+// PrivateChat returns the private chat of the user with the bot.
+func (user *User) PrivateChat() *aquagram.Chat {
+	return PrivateChat(user.User)
+}
+
+// Send simulates the user sending text in its private chat with the bot.
+func (user *User) Send(text string) *Result {
+	return user.SendTo(user.PrivateChat(), text)
+}
+
+// SendTo simulates the user sending text in chat.
+func (user *User) SendTo(chat *aquagram.Chat, text string) *Result {
+	message := user.harness.Server.UserMessage(user.User, chat, text)
+	return user.harness.Dispatch(&aquagram.Update{Message: message})
+}
+
+// Press simulates the user pressing an inline keyboard button of message with callback data.
+func (user *User) Press(message *aquagram.Message, data string) *Result {
+	callback := user.harness.Server.UserCallbackQuery(user.User, message, data)
+	return user.harness.Dispatch(&aquagram.Update{CallbackQuery: callback})
+}
+
+// PressButton simulates the user pressing the inline keyboard button of message labeled text.
+func (user *User) PressButton(message *aquagram.Message, text string) *Result {
+	user.harness.t.Helper()
+
+	if stored := user.harness.Server.Message(message.Chat.ID, message.MessageID); stored != nil {
+		message = stored
+	}
+
+	if message.ReplyMarkup != nil {
+		for _, row := range message.ReplyMarkup.InlineKeyboard {
+			for _, button := range row {
+				if button.Text == text {
+					return user.Press(message, button.CallbackData)
+				}
+			}
+		}
+	}
+
+	user.harness.t.Fatalf("message %d has no button %q", message.MessageID, text)
+	return nil
+}
+
+// Result holds what happened while dispatching an update.
+type Result struct {
+	Update *aquagram.Update
+
+	// Errors returned by the handlers.
+	Err error
+
+	// Requests made by the bot, in order.
+	Requests []*Request
+}
+
+// Calls returns the requests made for method.
+func (result *Result) Calls(method string) []*Request {
+	requests := make([]*Request, 0)
+
+	for _, request := range result.Requests {
+		if request.Method == method {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// Messages returns the messages sent by the bot.
+func (result *Result) Messages() []*aquagram.Message {
+	messages := make([]*aquagram.Message, 0)
+
+	for _, request := range result.Requests {
+		if !strings.HasPrefix(request.Method, "send") &&
+			!strings.HasPrefix(request.Method, "forward") {
+			continue
+		}
+
+		switch v := request.Result.(type) {
+		case *aquagram.Message:
+			messages = append(messages, v)
+		case []*aquagram.Message:
+			messages = append(messages, v...)
+		}
+	}
+
+	return messages
+}
+
+// Texts returns the text of the messages sent by the bot.
+func (result *Result) Texts() []string {
+	texts := make([]string, 0)
+
+	for _, message := range result.Messages() {
+		texts = append(texts, message.Text)
+	}
+
+	return texts
+}
+
+// Edits returns the messages edited by the bot.
+func (result *Result) Edits() []*aquagram.Message {
+	messages := make([]*aquagram.Message, 0)
+
+	for _, request := range result.Requests {
+		if !strings.HasPrefix(request.Method, "editMessage") {
+			continue
+		}
+
+		if message, ok := request.Result.(*aquagram.Message); ok {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+// CallbackAnswers returns the answers to callback queries sent by the bot.
+func (result *Result) CallbackAnswers() []*aquagram.AnswerCallbackQueryParams {
+	answers := make([]*aquagram.AnswerCallbackQueryParams, 0)
+
+	for _, request := range result.Calls("answerCallbackQuery") {
+		answer := new(aquagram.AnswerCallbackQueryParams)
+		answer.CallbackQueryID = request.String("callback_query_id")
+		answer.Text = request.String("text")
+		answer.ShowAlert = request.Bool("show_alert")
+		answer.URL = request.String("url")
+		answer.CacheTimeRaw = request.Int64("cache_time")
+
+		answers = append(answers, answer)
+	}
+
+	return answers
+}
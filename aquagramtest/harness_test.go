@@ -0,0 +1,78 @@
+package aquagramtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestHarness(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	h.Bot.OnCommand("start", func(bot *aquagram.Bot, message *aquagram.Message) error {
+		_, err := message.Reply("choose", &aquagram.SendMessageParams{
+			ReplyMarkup: &aquagram.InlineKeyboardMarkup{
+				InlineKeyboard: [][]*aquagram.InlineKeyboardButton{{
+					{Text: "Yes", CallbackData: "answer:yes"},
+				}},
+			},
+		})
+
+		return err
+	})
+
+	h.Bot.OnCallbackQuery("answer:", false, func(bot *aquagram.Bot, callback *aquagram.CallbackQuery) error {
+		if _, err := callback.Message.EditText("done", nil); err != nil {
+			return err
+		}
+
+		return callback.Answer(&aquagram.AnswerCallbackQueryParams{Text: callback.Data})
+	})
+
+	user := h.User(42)
+
+	res := user.Send("/start")
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	messages := res.Messages()
+	if len(messages) != 1 || messages[0].Text != "choose" {
+		t.Fatalf("unexpected messages %v", res.Texts())
+	}
+
+	res = user.PressButton(messages[0], "Yes")
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if edits := res.Edits(); len(edits) != 1 || edits[0].Text != "done" {
+		t.Errorf("unexpected edits %+v", edits)
+	}
+
+	answers := res.CallbackAnswers()
+	if len(answers) != 1 || answers[0].Text != "answer:yes" {
+		t.Errorf("unexpected callback answers %+v", answers)
+	}
+}
+
+func TestHarnessError(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	h.Bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		_, err := bot.SendMessage("-100", "hello", nil)
+		return err
+	})
+
+	res := h.User(42).Send("hello")
+
+	if !errors.Is(res.Err, aquagram.ErrTgChatNotFound) {
+		t.Errorf("expected ErrTgChatNotFound, got %v", res.Err)
+	}
+
+	if len(res.Calls("sendMessage")) != 1 {
+		t.Errorf("expected a sendMessage call")
+	}
+}
@@ -0,0 +1,98 @@
+package aquagramtest
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/aquagram/aquagram"
+)
+
+/*
+InjectMessage simulates a message sent by a user to chat.
+
+The chat and the user as a member of it are added to the server if unknown,
+leading commands like /start get their bot_command entity.
+*/
+func (server *Server) InjectMessage(from *aquagram.User, chat *aquagram.Chat, text string) (*aquagram.Message, error) {
+	message := server.UserMessage(from, chat, text)
+	return message, server.PushUpdate(&aquagram.Update{Message: message})
+}
+
+// UserMessage stores a message sent by a user to chat, without delivering it to the bot.
+func (server *Server) UserMessage(from *aquagram.User, chat *aquagram.Chat, text string) *aquagram.Message {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	message := server.newUserMessage(from, chat)
+	message.Text = text
+	message.Entities = commandEntities(text)
+
+	return server.storeMessage(message)
+}
+
+/*
+InjectCallbackQuery simulates a user pressing an inline keyboard
+button with callback data attached to message.
+*/
+func (server *Server) InjectCallbackQuery(from *aquagram.User, message *aquagram.Message, data string) (*aquagram.CallbackQuery, error) {
+	callback := server.UserCallbackQuery(from, message, data)
+	return callback, server.PushUpdate(&aquagram.Update{CallbackQuery: callback})
+}
+
+// UserCallbackQuery builds a callback query sent by a user, without delivering it to the bot.
+func (server *Server) UserCallbackQuery(from *aquagram.User, message *aquagram.Message, data string) *aquagram.CallbackQuery {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.lastCallbackID++
+
+	callback := new(aquagram.CallbackQuery)
+	callback.ID = strconv.Itoa(server.lastCallbackID)
+	callback.From = from
+	callback.Data = data
+
+	if message != nil {
+		if stored := server.message(message.Chat.ID, message.MessageID); stored != nil {
+			message = stored
+		}
+
+		copied := *message
+		callback.Message = &copied
+		callback.ChatInstance = strconv.FormatInt(message.Chat.ID, 10)
+	}
+
+	return callback
+}
+
+func (server *Server) newUserMessage(from *aquagram.User, chat *aquagram.Chat) *aquagram.Message {
+	if known, ok := server.chats[chat.ID]; ok {
+		chat = known
+	} else {
+		server.chats[chat.ID] = chat
+	}
+
+	if _, ok := server.members[chat.ID][from.ID]; !ok && !chat.IsPrivate() {
+		server.setChatMember(chat.ID, &aquagram.ChatMember{
+			Status: aquagram.ChatMemberStatusMember,
+			User:   from,
+		})
+	}
+
+	return server.newMessage(from, chat)
+}
+
+// commandEntities returns the bot_command entity of a text starting with a command.
+func commandEntities(text string) []*aquagram.MessageEntity {
+	if !strings.HasPrefix(text, "/") {
+		return nil
+	}
+
+	command, _, _ := strings.Cut(text, " ")
+
+	return []*aquagram.MessageEntity{{
+		Type:   aquagram.EntityTypeBotCommand,
+		Offset: 0,
+		Length: len(utf16.Encode([]rune(command))),
+	}}
+}
@@ -0,0 +1,1076 @@
+package aquagramtest
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+var defaultHandlers = map[string]HandlerFunc{
+	"getMe":                           getMe,
+	"logOut":                          returnTrue,
+	"close":                           returnTrue,
+	"getMyName":                       getMyName,
+	"setMyName":                       setMyName,
+	"getUpdates":                      getUpdates,
+	"setWebhook":                      setWebhook,
+	"deleteWebhook":                   deleteWebhook,
+	"getWebhookInfo":                  getWebhookInfo,
+	"sendMessage":                     sendMessage,
+	"sendDocument":                    sendMedia("document"),
+	"sendPhoto":                       sendMedia("photo"),
+	"sendAudio":                       sendMedia("audio"),
+	"sendVideo":                       sendMedia("video"),
+	"sendMediaGroup":                  sendMediaGroup,
+	"forwardMessage":                  forwardMessage,
+	"copyMessage":                     copyMessage,
+	"editMessageText":                 editMessageText,
+	"deleteMessage":                   deleteMessage,
+	"deleteMessages":                  deleteMessages,
+	"answerCallbackQuery":             returnTrue,
+	"answerInlineQuery":               returnTrue,
+	"getChatMember":                   getChatMember,
+	"getChatAdministrators":           getChatAdministrators,
+	"getChatMemberCount":              getChatMemberCount,
+	"setChatTitle":                    setChatTitle,
+	"setChatDescription":              withChat(returnTrue),
+	"setChatStickerSet":               withChat(returnTrue),
+	"deleteChatStickerSet":            withChat(returnTrue),
+	"leaveChat":                       leaveChat,
+	"banChatMember":                   banChatMember,
+	"unbanChatMember":                 unbanChatMember,
+	"restrictChatMember":              restrictChatMember,
+	"promoteChatMember":               promoteChatMember,
+	"setChatAdministratorCustomTitle": setChatAdministratorCustomTitle,
+	"sendInvoice":                     sendInvoice,
+	"createInvoiceLink":               createInvoiceLink,
+	"answerShippingQuery":             returnTrue,
+	"answerPreCheckoutQuery":          returnTrue,
+	"refundStarPayment":               returnTrue,
+	"getStarTransactions":             getStarTransactions,
+	"sendPoll":                        sendPoll,
+	"stopPoll":                        stopPoll,
+	"approveChatJoinRequest":          approveChatJoinRequest,
+	"declineChatJoinRequest":          withChat(returnTrue),
+	"createChatInviteLink":            createChatInviteLink,
+	"editChatInviteLink":              editChatInviteLink,
+	"revokeChatInviteLink":            revokeChatInviteLink,
+	"exportChatInviteLink":            exportChatInviteLink,
+}
+
+var (
+	errChatNotFound            = NewError(400, "Bad Request: chat not found")
+	errMessageTextEmpty        = NewError(400, "Bad Request: message text is empty")
+	errInvoicePricesInvalid    = NewError(400, "Bad Request: CURRENCY_TOTAL_AMOUNT_INVALID")
+	errMessageToEditNotFound   = NewError(400, "Bad Request: message to edit not found")
+	errPollOptionsInvalid      = NewError(400, "Bad Request: poll must have at least 2 option")
+	errPollToStopNotFound      = NewError(400, "Bad Request: message with poll to stop not found")
+	errPollAlreadyClosed       = NewError(400, "Bad Request: poll has already been closed")
+	errInviteLinkNotFound      = NewError(400, "Bad Request: INVITE_HASH_EXPIRED")
+	errInviteLinkMemberLimit   = NewError(400, "Bad Request: member limit can't be specified for links requiring administrator approval")
+	errMessageToDeleteNotFound = NewError(400, "Bad Request: message to delete not found")
+	errMessageNotModified      = NewError(400, "Bad Request: message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	errUserNotFound            = NewError(400, "Bad Request: user not found")
+	errWebhookActive           = NewError(409, "Conflict: can't use getUpdates method while webhook is active; use deleteWebhook to delete the webhook first")
+)
+
+func returnTrue(server *Server, request *Request) (any, error) {
+	return true, nil
+}
+
+// withChat checks that the chat_id parameter references a known chat.
+func withChat(handler HandlerFunc) HandlerFunc {
+	return func(server *Server, request *Request) (any, error) {
+		server.mu.Lock()
+		_, err := server.resolveChat(request.String("chat_id"))
+		server.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(server, request)
+	}
+}
+
+func (server *Server) resolveChat(chatID string) (*aquagram.Chat, error) {
+	if username, ok := strings.CutPrefix(chatID, "@"); ok {
+		for _, chat := range server.chats {
+			if strings.EqualFold(chat.Username, username) {
+				return chat, nil
+			}
+		}
+
+		return nil, errChatNotFound
+	}
+
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return nil, errChatNotFound
+	}
+
+	chat, ok := server.chats[id]
+	if !ok {
+		return nil, errChatNotFound
+	}
+
+	return chat, nil
+}
+
+func (server *Server) newMessage(from *aquagram.User, chat *aquagram.Chat) *aquagram.Message {
+	server.lastMessageID++
+
+	message := new(aquagram.Message)
+	message.MessageID = server.lastMessageID
+	message.From = from
+	message.Chat = chat
+	message.Date = time.Now().Unix()
+
+	return message
+}
+
+// newBotMessage builds a message sent by the bot with the common parameters of request.
+func (server *Server) newBotMessage(chat *aquagram.Chat, request *Request) *aquagram.Message {
+	message := server.newMessage(server.Me, chat)
+	message.MessageThreadID = request.Int64("message_thread_id")
+	message.BusinessConnectionID = request.String("business_connection_id")
+	message.HasProtectedContent = request.Bool("protect_content")
+	message.EffectID = request.String("message_effect_id")
+	message.Caption = request.String("caption")
+	message.ShowCaptionAboveMedia = request.Bool("show_caption_above_media")
+	message.HasMediaSpoiler = request.Bool("has_spoiler")
+
+	request.Decode("caption_entities", &message.CaptionEntities)
+	request.Decode("reply_markup", &message.ReplyMarkup)
+
+	var replyParameters aquagram.ReplyParameters
+	if request.Decode("reply_parameters", &replyParameters) == nil && replyParameters.MessageID != 0 {
+		message.ReplyToMessage = server.message(chat.ID, replyParameters.MessageID)
+	}
+
+	return message
+}
+
+func (server *Server) storeMessage(message *aquagram.Message) *aquagram.Message {
+	server.messages[message.Chat.ID] = append(server.messages[message.Chat.ID], message)
+
+	copied := *message
+	return &copied
+}
+
+// inputFile returns the file sent in field, uploaded or referenced by file_id or URL.
+func (server *Server) inputFile(request *Request, field string) (*File, error) {
+	if file, ok := request.Files[field]; ok {
+		server.lastFileID++
+		file.FileID = "file-" + strconv.Itoa(server.lastFileID)
+		server.files[file.FileID] = file
+
+		return file, nil
+	}
+
+	value := request.String(field)
+
+	if name, ok := strings.CutPrefix(value, "attach://"); ok {
+		if _, ok := request.Files[name]; ok {
+			return server.inputFile(request, name)
+		}
+	}
+
+	if value == aquagram.EmptyString {
+		return nil, NewError(400, "Bad Request: there is no "+field+" in the request")
+	}
+
+	if file, ok := server.files[value]; ok {
+		return file, nil
+	}
+
+	file := new(File)
+	file.FileID = value
+
+	return file, nil
+}
+
+func getMe(server *Server, request *Request) (any, error) {
+	return server.Me, nil
+}
+
+func getMyName(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return aquagram.BotName{Name: server.botName}, nil
+}
+
+func setMyName(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.botName = request.String("name")
+
+	return true, nil
+}
+
+// confirm forgets the updates confirmed by offset.
+func (server *Server) confirm(offset int) {
+	if offset < 0 {
+		if keep := -offset; len(server.updates) > keep {
+			server.updates = server.updates[len(server.updates)-keep:]
+		}
+
+		return
+	}
+
+	for len(server.updates) > 0 && server.updates[0].UpdateID < offset {
+		server.updates = server.updates[1:]
+	}
+}
+
+func getUpdates(server *Server, request *Request) (any, error) {
+	limit := request.Int("limit")
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	timer := time.NewTimer(time.Duration(request.Int("timeout")) * time.Second)
+	defer timer.Stop()
+
+	for {
+		server.mu.Lock()
+
+		if server.webhook != nil {
+			server.mu.Unlock()
+			return nil, errWebhookActive
+		}
+
+		server.confirm(request.Int("offset"))
+
+		updates := server.updates[:min(limit, len(server.updates))]
+		updates = append([]*aquagram.Update(nil), updates...)
+		signal := server.updatesSignal
+
+		server.mu.Unlock()
+
+		if len(updates) > 0 {
+			return updates, nil
+		}
+
+		select {
+		case <-signal:
+		case <-timer.C:
+			return updates, nil
+		case <-server.closed:
+			return updates, nil
+		case <-request.ctx.Done():
+			return updates, nil
+		}
+	}
+}
+
+func setWebhook(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	url := request.String("url")
+	if url == aquagram.EmptyString {
+		server.webhook = nil
+		return true, nil
+	}
+
+	webhook := new(aquagram.SetWebhookParams)
+	webhook.URL = url
+	webhook.IPAddress = request.String("ip_address")
+	webhook.MaxConnections = request.Int("max_connections")
+	webhook.DropPendingUpdates = request.Bool("drop_pending_updates")
+	webhook.SecretToken = request.String("secret_token")
+
+	request.Decode("allowed_updates", &webhook.AllowedUpdates)
+
+	if file, ok := request.Files["certificate"]; ok {
+		webhook.Certificate = &aquagram.InputFile{FileName: file.FileName}
+	}
+
+	if webhook.DropPendingUpdates {
+		server.updates = nil
+	}
+
+	server.webhook = webhook
+
+	return true, nil
+}
+
+func deleteWebhook(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.webhook = nil
+
+	if request.Bool("drop_pending_updates") {
+		server.updates = nil
+	}
+
+	return true, nil
+}
+
+func getWebhookInfo(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	info := new(aquagram.WebhookInfo)
+	info.PendingUpdatesCount = len(server.updates)
+
+	if webhook := server.webhook; webhook != nil {
+		info.URL = webhook.URL
+		info.HasCustomCertificate = webhook.Certificate != nil
+		info.IpAddress = webhook.IPAddress
+		info.MaxConnections = webhook.MaxConnections
+		info.AllowedUpdates = webhook.AllowedUpdates
+	}
+
+	return info, nil
+}
+
+func sendMessage(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	text := request.String("text")
+	if strings.TrimSpace(text) == aquagram.EmptyString {
+		return nil, errMessageTextEmpty
+	}
+
+	message := server.newBotMessage(chat, request)
+	message.Text = text
+
+	request.Decode("entities", &message.Entities)
+	request.Decode("link_preview_options", &message.LinkPreviewOptions)
+
+	return server.storeMessage(message), nil
+}
+
+func sendMedia(field string) HandlerFunc {
+	return func(server *Server, request *Request) (any, error) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		chat, err := server.resolveChat(request.String("chat_id"))
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := server.inputFile(request, field)
+		if err != nil {
+			return nil, err
+		}
+
+		message := server.newBotMessage(chat, request)
+		setMedia(message, field, file, request.Params)
+
+		return server.storeMessage(message), nil
+	}
+}
+
+func setMedia(message *aquagram.Message, mediaType string, file *File, params map[string]string) {
+	size := int64(len(file.Data))
+	duration, _ := strconv.Atoi(params["duration"])
+	width, _ := strconv.Atoi(params["width"])
+	height, _ := strconv.Atoi(params["height"])
+
+	switch mediaType {
+	case "document":
+		message.Document = &aquagram.Document{
+			FileID:       file.FileID,
+			FileUniqueID: file.FileID,
+			FileName:     file.FileName,
+			FileSize:     size,
+		}
+
+	case "photo":
+		message.Photo = []aquagram.PhotoSize{{
+			FileID:       file.FileID,
+			FileUniqueID: file.FileID,
+			Width:        width,
+			Height:       height,
+			FileSize:     int(size),
+		}}
+
+	case "audio":
+		message.Audio = &aquagram.Audio{
+			FileID:       file.FileID,
+			FileUniqueID: file.FileID,
+			Duration:     duration,
+			Performer:    params["performer"],
+			Title:        params["title"],
+			FileName:     file.FileName,
+			FileSize:     size,
+		}
+
+	case "video":
+		message.Video = &aquagram.Video{
+			FileID:       file.FileID,
+			FileUniqueID: file.FileID,
+			Width:        width,
+			Height:       height,
+			Duration:     duration,
+			FileName:     file.FileName,
+			FileSize:     size,
+		}
+	}
+}
+
+func sendMediaGroup(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	var media []map[string]string
+	if err := request.Decode("media", &media); err != nil {
+		return nil, NewError(400, "Bad Request: can't parse media JSON object")
+	}
+
+	if len(media) < 2 || len(media) > 10 {
+		return nil, NewError(400, "Bad Request: wrong number of messages in the media group")
+	}
+
+	mediaGroupID := strconv.FormatInt(server.lastMessageID+1, 10)
+	messages := make([]*aquagram.Message, 0, len(media))
+
+	for _, item := range media {
+		itemRequest := &Request{Params: item, Files: request.Files}
+
+		file, err := server.inputFile(itemRequest, "media")
+		if err != nil {
+			return nil, err
+		}
+
+		message := server.newBotMessage(chat, request)
+		message.MediaGroupID = mediaGroupID
+		message.Caption = item["caption"]
+
+		setMedia(message, item["type"], file, item)
+
+		messages = append(messages, server.storeMessage(message))
+	}
+
+	return messages, nil
+}
+
+// duplicate copies the content of message to chat, as sent by the bot.
+func (server *Server) duplicate(request *Request, chat *aquagram.Chat, message *aquagram.Message) *aquagram.Message {
+	copied := server.newBotMessage(chat, request)
+	copied.Text = message.Text
+	copied.Entities = message.Entities
+	copied.Animation = message.Animation
+	copied.Audio = message.Audio
+	copied.Document = message.Document
+	copied.Photo = message.Photo
+	copied.Video = message.Video
+	copied.Voice = message.Voice
+
+	if copied.Caption == aquagram.EmptyString {
+		copied.Caption = message.Caption
+		copied.CaptionEntities = message.CaptionEntities
+	}
+
+	return copied
+}
+
+func (server *Server) sourceMessage(request *Request) (*aquagram.Chat, *aquagram.Message, error) {
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fromChat, err := server.resolveChat(request.String("from_chat_id"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := server.message(fromChat.ID, request.Int64("message_id"))
+	if message == nil {
+		return nil, nil, NewError(400, "Bad Request: message to copy not found")
+	}
+
+	return chat, message, nil
+}
+
+func forwardMessage(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, message, err := server.sourceMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	forwarded := server.duplicate(request, chat, message)
+	forwarded.Caption = message.Caption
+	forwarded.CaptionEntities = message.CaptionEntities
+
+	return server.storeMessage(forwarded), nil
+}
+
+func copyMessage(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, message, err := server.sourceMessage(request)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := server.storeMessage(server.duplicate(request, chat, message))
+
+	return aquagram.MessageID{MessageID: copied.MessageID}, nil
+}
+
+func editMessageText(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if request.String("inline_message_id") != aquagram.EmptyString {
+		return true, nil
+	}
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	message := server.message(chat.ID, request.Int64("message_id"))
+	if message == nil {
+		return nil, errMessageToEditNotFound
+	}
+
+	text := request.String("text")
+	if strings.TrimSpace(text) == aquagram.EmptyString {
+		return nil, errMessageTextEmpty
+	}
+
+	if message.Text == text && !request.Has("reply_markup") {
+		return nil, errMessageNotModified
+	}
+
+	message.Text = text
+	message.Entities = nil
+	message.EditDate = time.Now().Unix()
+
+	request.Decode("entities", &message.Entities)
+	request.Decode("reply_markup", &message.ReplyMarkup)
+
+	copied := *message
+	return &copied, nil
+}
+
+func (server *Server) deleteMessage(chatID int64, messageID int64) bool {
+	messages := server.messages[chatID]
+
+	for i, message := range messages {
+		if message.MessageID == messageID {
+			server.messages[chatID] = append(messages[:i:i], messages[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func deleteMessage(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !server.deleteMessage(chat.ID, request.Int64("message_id")) {
+		return nil, errMessageToDeleteNotFound
+	}
+
+	return true, nil
+}
+
+func deleteMessages(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	var messageIDs []int64
+	if err := request.Decode("message_ids", &messageIDs); err != nil {
+		return nil, NewError(400, "Bad Request: can't parse message identifiers JSON object")
+	}
+
+	for _, messageID := range messageIDs {
+		server.deleteMessage(chat.ID, messageID)
+	}
+
+	return true, nil
+}
+
+func (server *Server) chatMember(chat *aquagram.Chat, userID int64) *aquagram.ChatMember {
+	if member, ok := server.members[chat.ID][userID]; ok {
+		return member
+	}
+
+	member := &aquagram.ChatMember{
+		Status: aquagram.ChatMemberStatusLeft,
+		User:   &aquagram.User{ID: userID},
+	}
+
+	if chat.IsPrivate() && (userID == chat.ID || userID == server.Me.ID) {
+		member.Status = aquagram.ChatMemberStatusMember
+	}
+
+	return member
+}
+
+func getChatMember(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	userID := request.Int64("user_id")
+	if userID <= 0 {
+		return nil, errUserNotFound
+	}
+
+	return server.chatMember(chat, userID), nil
+}
+
+func getChatAdministrators(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	admins := make([]*aquagram.ChatMember, 0)
+
+	for _, member := range server.members[chat.ID] {
+		if member.IsOwner() || member.IsAdministrator() {
+			admins = append(admins, member)
+		}
+	}
+
+	return admins, nil
+}
+
+func getChatMemberCount(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	if chat.IsPrivate() {
+		return 2, nil
+	}
+
+	count := 0
+
+	for _, member := range server.members[chat.ID] {
+		if !member.IsLeft() && !member.IsKicked() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func setChatTitle(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	chat.Title = request.String("title")
+
+	return true, nil
+}
+
+func leaveChat(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	server.setChatMember(chat.ID, &aquagram.ChatMember{
+		Status: aquagram.ChatMemberStatusLeft,
+		User:   server.Me,
+	})
+
+	return true, nil
+}
+
+// updateChatMember applies update to the member referenced by the chat_id and user_id parameters.
+func (server *Server) updateChatMember(request *Request, update func(member *aquagram.ChatMember)) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	userID := request.Int64("user_id")
+	if userID <= 0 {
+		return nil, errUserNotFound
+	}
+
+	member := *server.chatMember(chat, userID)
+	update(&member)
+
+	server.setChatMember(chat.ID, &member)
+
+	return true, nil
+}
+
+func banChatMember(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		member.Status = aquagram.ChatMemberStatusKicked
+		member.UntilDate = request.Int64("until_date")
+	})
+}
+
+func unbanChatMember(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		if request.Bool("only_if_banned") && !member.IsKicked() {
+			return
+		}
+
+		member.Status = aquagram.ChatMemberStatusLeft
+		member.UntilDate = 0
+	})
+}
+
+func restrictChatMember(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		member.Status = aquagram.ChatMemberStatusRestricted
+		member.ChatPermissions = aquagram.ChatPermissions{}
+		member.UntilDate = request.Int64("until_date")
+
+		request.Decode("permissions", &member.ChatPermissions)
+	})
+}
+
+func promoteChatMember(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		flags := make(map[string]bool)
+
+		for key := range request.Params {
+			if strings.HasPrefix(key, "can_") {
+				flags[key] = request.Bool(key)
+			}
+		}
+
+		data, _ := json.Marshal(flags)
+
+		member.ChatMemberAdministratorPermissions = aquagram.ChatMemberAdministratorPermissions{}
+		json.Unmarshal(data, &member.ChatMemberAdministratorPermissions)
+
+		member.Status = aquagram.ChatMemberStatusMember
+
+		for _, value := range flags {
+			if value {
+				member.Status = aquagram.ChatMemberStatusAdministrator
+				break
+			}
+		}
+	})
+}
+
+func setChatAdministratorCustomTitle(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		member.CustomTitle = request.String("custom_title")
+	})
+}
+
+// invoice validates the invoice parameters of request.
+func invoice(request *Request) (*aquagram.Invoice, error) {
+	var prices []aquagram.LabeledPrice
+	request.Decode("prices", &prices)
+
+	invoice := new(aquagram.Invoice)
+	invoice.Title = request.String("title")
+	invoice.Description = request.String("description")
+	invoice.StartParameter = request.String("start_parameter")
+	invoice.Currency = request.String("currency")
+
+	for _, price := range prices {
+		invoice.TotalAmount += price.Amount
+	}
+
+	if len(prices) == 0 || invoice.TotalAmount <= 0 {
+		return nil, errInvoicePricesInvalid
+	}
+
+	return invoice, nil
+}
+
+func sendInvoice(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := invoice(request)
+	if err != nil {
+		return nil, err
+	}
+
+	message := server.newBotMessage(chat, request)
+	message.Invoice = invoice
+
+	return server.storeMessage(message), nil
+}
+
+func createInvoiceLink(server *Server, request *Request) (any, error) {
+	if _, err := invoice(request); err != nil {
+		return nil, err
+	}
+
+	return "https://t.me/$" + request.String("payload"), nil
+}
+
+func getStarTransactions(server *Server, request *Request) (any, error) {
+	return &aquagram.StarTransactions{Transactions: []*aquagram.StarTransaction{}}, nil
+}
+
+func sendPoll(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	var options []aquagram.InputPollOption
+	request.Decode("options", &options)
+
+	if len(options) < 2 || len(options) > 10 {
+		return nil, errPollOptionsInvalid
+	}
+
+	if strings.TrimSpace(request.String("question")) == aquagram.EmptyString {
+		return nil, NewError(400, "Bad Request: poll question must be non-empty")
+	}
+
+	message := server.newBotMessage(chat, request)
+
+	poll := new(aquagram.Poll)
+	poll.ID = "poll-" + strconv.FormatInt(message.MessageID, 10)
+	poll.Question = request.String("question")
+	poll.IsAnonymous = !request.Has("is_anonymous") || request.Bool("is_anonymous")
+	poll.Type = aquagram.PollTypeRegular
+	poll.AllowsMultipleAnswers = request.Bool("allows_multiple_answers")
+	poll.Explanation = request.String("explanation")
+	poll.OpenPeriod = request.Int("open_period")
+	poll.CloseDate = request.Int64("close_date")
+	poll.IsClosed = request.Bool("is_closed")
+
+	if request.Has("type") {
+		poll.Type = aquagram.PollType(request.String("type"))
+	}
+
+	if poll.Type == aquagram.PollTypeQuiz {
+		if !request.Has("correct_option_id") {
+			return nil, NewError(400, "Bad Request: wrong correct option ID specified")
+		}
+
+		correctOptionID := request.Int("correct_option_id")
+		if correctOptionID < 0 || correctOptionID >= len(options) {
+			return nil, NewError(400, "Bad Request: wrong correct option ID specified")
+		}
+
+		poll.CorrectOptionID = &correctOptionID
+	}
+
+	request.Decode("question_entities", &poll.QuestionEntities)
+	request.Decode("explanation_entities", &poll.ExplanationEntities)
+
+	poll.Options = make([]aquagram.PollOption, len(options))
+
+	for i, option := range options {
+		poll.Options[i].Text = option.Text
+		poll.Options[i].TextEntities = option.TextEntities
+	}
+
+	message.Poll = poll
+
+	return server.storeMessage(message), nil
+}
+
+func stopPoll(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	chat, err := server.resolveChat(request.String("chat_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	message := server.message(chat.ID, request.Int64("message_id"))
+	if message == nil || message.Poll == nil {
+		return nil, errPollToStopNotFound
+	}
+
+	if message.Poll.IsClosed {
+		return nil, errPollAlreadyClosed
+	}
+
+	message.Poll.IsClosed = true
+
+	copied := *message.Poll
+	return &copied, nil
+}
+
+func approveChatJoinRequest(server *Server, request *Request) (any, error) {
+	return server.updateChatMember(request, func(member *aquagram.ChatMember) {
+		if !member.IsPresent() {
+			member.Status = aquagram.ChatMemberStatusMember
+		}
+	})
+}
+
+func (server *Server) newInviteLink() *aquagram.ChatInviteLink {
+	server.lastInviteLinkID++
+
+	link := new(aquagram.ChatInviteLink)
+	link.InviteLink = "https://t.me/+link" + strconv.Itoa(server.lastInviteLinkID)
+	link.Creator = server.Me
+
+	server.inviteLinks[link.InviteLink] = link
+
+	return link
+}
+
+// setInviteLinkParams sets the editable fields of link from request.
+func setInviteLinkParams(link *aquagram.ChatInviteLink, request *Request) error {
+	link.Name = request.String("name")
+	link.ExpireDate = request.Int64("expire_date")
+	link.MemberLimit = request.Int("member_limit")
+	link.CreatesJoinRequest = request.Bool("creates_join_request")
+
+	if link.CreatesJoinRequest && link.MemberLimit != 0 {
+		return errInviteLinkMemberLimit
+	}
+
+	return nil
+}
+
+func createChatInviteLink(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if _, err := server.resolveChat(request.String("chat_id")); err != nil {
+		return nil, err
+	}
+
+	var link aquagram.ChatInviteLink
+	if err := setInviteLinkParams(&link, request); err != nil {
+		return nil, err
+	}
+
+	created := server.newInviteLink()
+	link.InviteLink = created.InviteLink
+	link.Creator = created.Creator
+
+	*created = link
+
+	return &link, nil
+}
+
+func (server *Server) inviteLink(request *Request) (*aquagram.ChatInviteLink, error) {
+	if _, err := server.resolveChat(request.String("chat_id")); err != nil {
+		return nil, err
+	}
+
+	link, ok := server.inviteLinks[request.String("invite_link")]
+	if !ok {
+		return nil, errInviteLinkNotFound
+	}
+
+	return link, nil
+}
+
+func editChatInviteLink(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	link, err := server.inviteLink(request)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := *link
+	if err := setInviteLinkParams(&edited, request); err != nil {
+		return nil, err
+	}
+
+	*link = edited
+
+	return &edited, nil
+}
+
+func revokeChatInviteLink(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	link, err := server.inviteLink(request)
+	if err != nil {
+		return nil, err
+	}
+
+	link.IsRevoked = true
+
+	copied := *link
+	return &copied, nil
+}
+
+func exportChatInviteLink(server *Server, request *Request) (any, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if _, err := server.resolveChat(request.String("chat_id")); err != nil {
+		return nil, err
+	}
+
+	link := server.newInviteLink()
+	link.IsPrimary = true
+
+	return link.InviteLink, nil
+}
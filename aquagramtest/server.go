@@ -0,0 +1,601 @@
+/*
+Package aquagramtest provides a fake Bot API server to test bots offline.
+
+The [Server] keeps an in-memory model of chats, members and messages,
+records every request made by the bot and lets tests inject updates
+as if they were sent by users:
+
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	bot.OnCommand("start", StartCommandHandler)
+
+	go bot.StartPolling(false)
+	defer bot.Stop()
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+	server.InjectMessage(user, aquagramtest.PrivateChat(user), "/start")
+*/
+package aquagramtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+const DefaultToken = "123456:TEST-TOKEN"
+
+// HandlerFunc implements a Bot API method, its result is encoded as JSON.
+//
+// Returning an [*aquagram.APIError] answers the request with "ok": false.
+type HandlerFunc func(server *Server, request *Request) (any, error)
+
+// Request is an API request received by the [Server].
+type Request struct {
+	Method string
+	Params map[string]string
+	Files  map[string]*File
+	Time   time.Time
+
+	// Result answered by the server, nil if the request failed.
+	Result any
+
+	// Error answered by the server, nil if the request succeeded.
+	Err error
+
+	ctx context.Context
+}
+
+// File is a file uploaded within a multipart request.
+type File struct {
+	FileID   string
+	FileName string
+	Data     []byte
+}
+
+func (request *Request) Has(key string) bool {
+	_, ok := request.Params[key]
+	return ok
+}
+
+func (request *Request) String(key string) string {
+	return request.Params[key]
+}
+
+func (request *Request) Int64(key string) int64 {
+	value, _ := strconv.ParseInt(request.Params[key], 10, 64)
+	return value
+}
+
+func (request *Request) Int(key string) int {
+	value, _ := strconv.Atoi(request.Params[key])
+	return value
+}
+
+func (request *Request) Bool(key string) bool {
+	value, _ := strconv.ParseBool(request.Params[key])
+	return value
+}
+
+// Decode decodes a JSON serialized parameter into v.
+func (request *Request) Decode(key string, v any) error {
+	value, ok := request.Params[key]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(value), v)
+}
+
+// Server is a fake Bot API server running on a local [httptest.Server].
+type Server struct {
+	// Only requests using this token are accepted, by default is [DefaultToken].
+	Token string
+
+	// The bot returned by getMe.
+	Me *aquagram.User
+
+	server *httptest.Server
+	closed chan struct{}
+
+	mu            sync.Mutex
+	handlers      map[string]HandlerFunc
+	requests      []*Request
+	chats         map[int64]*aquagram.Chat
+	members       map[int64]map[int64]*aquagram.ChatMember
+	messages      map[int64][]*aquagram.Message
+	files         map[string]*File
+	updates       []*aquagram.Update
+	updatesSignal chan struct{}
+	webhook       *aquagram.SetWebhookParams
+	botName       string
+	lastMessageID int64
+	lastUpdateID  int
+	lastFileID    int
+
+	lastCallbackID int
+
+	inviteLinks      map[string]*aquagram.ChatInviteLink
+	lastInviteLinkID int
+}
+
+func NewServer() *Server {
+	server := new(Server)
+	server.Token = DefaultToken
+	server.Me = &aquagram.User{
+		ID:        123456,
+		IsBot:     true,
+		FirstName: "Aquagram",
+		Username:  "aquagram_test_bot",
+	}
+
+	server.closed = make(chan struct{})
+	server.handlers = make(map[string]HandlerFunc)
+	server.chats = make(map[int64]*aquagram.Chat)
+	server.members = make(map[int64]map[int64]*aquagram.ChatMember)
+	server.messages = make(map[int64][]*aquagram.Message)
+	server.files = make(map[string]*File)
+	server.inviteLinks = make(map[string]*aquagram.ChatInviteLink)
+	server.updatesSignal = make(chan struct{})
+	server.botName = server.Me.FirstName
+
+	for method, handler := range defaultHandlers {
+		server.handlers[method] = handler
+	}
+
+	server.server = httptest.NewServer(server)
+
+	return server
+}
+
+// URL returns the base URL of the server, to be used as [aquagram.Config.API].
+func (server *Server) URL() string {
+	return server.server.URL
+}
+
+func (server *Server) Close() {
+	select {
+	case <-server.closed:
+	default:
+		close(server.closed)
+	}
+
+	server.server.Close()
+}
+
+// NewBot returns a bot configured to use the server.
+func (server *Server) NewBot() *aquagram.Bot {
+	bot := aquagram.NewBot(server.Token)
+	bot.Config.API = server.URL()
+	bot.Config.Client = server.server.Client()
+	bot.Config.RetriesInterval = 10 * time.Millisecond
+
+	return bot
+}
+
+// Handle replaces the implementation of a method, e.g. to return errors.
+func (server *Server) Handle(method string, handler HandlerFunc) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.handlers[method] = handler
+}
+
+// Requests returns the requests received for method, or every request if method is empty.
+func (server *Server) Requests(method string) []*Request {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	requests := make([]*Request, 0)
+
+	for _, request := range server.requests {
+		if method == aquagram.EmptyString || request.Method == method {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// LastRequest returns the last request received for method, or nil.
+func (server *Server) LastRequest(method string) *Request {
+	requests := server.Requests(method)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	return requests[len(requests)-1]
+}
+
+// ResetRequests forgets the recorded requests.
+func (server *Server) ResetRequests() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.requests = nil
+}
+
+// AddChat adds or replaces a chat known by the server.
+func (server *Server) AddChat(chat *aquagram.Chat) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.chats[chat.ID] = chat
+}
+
+// Chat returns a chat known by the server, or nil.
+func (server *Server) Chat(chatID int64) *aquagram.Chat {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.chats[chatID]
+}
+
+// SetChatMember adds or replaces a member of a chat.
+func (server *Server) SetChatMember(chatID int64, member *aquagram.ChatMember) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.setChatMember(chatID, member)
+}
+
+func (server *Server) setChatMember(chatID int64, member *aquagram.ChatMember) {
+	members, ok := server.members[chatID]
+	if !ok {
+		members = make(map[int64]*aquagram.ChatMember)
+		server.members[chatID] = members
+	}
+
+	members[member.User.ID] = member
+}
+
+// ChatMember returns a member of a chat, or nil.
+func (server *Server) ChatMember(chatID int64, userID int64) *aquagram.ChatMember {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.members[chatID][userID]
+}
+
+// Messages returns the messages of a chat, sent by users or by the bot.
+func (server *Server) Messages(chatID int64) []*aquagram.Message {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]*aquagram.Message(nil), server.messages[chatID]...)
+}
+
+// SentMessages returns the messages sent by the bot to a chat.
+func (server *Server) SentMessages(chatID int64) []*aquagram.Message {
+	messages := make([]*aquagram.Message, 0)
+
+	for _, message := range server.Messages(chatID) {
+		if message.From != nil && message.From.ID == server.Me.ID {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+// Message returns a message of a chat, or nil.
+func (server *Server) Message(chatID int64, messageID int64) *aquagram.Message {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.message(chatID, messageID)
+}
+
+func (server *Server) message(chatID int64, messageID int64) *aquagram.Message {
+	for _, message := range server.messages[chatID] {
+		if message.MessageID == messageID {
+			return message
+		}
+	}
+
+	return nil
+}
+
+// File returns an uploaded file by its file_id, or nil.
+func (server *Server) File(fileID string) *File {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.files[fileID]
+}
+
+// Webhook returns the current webhook, or nil if the bot uses getUpdates.
+func (server *Server) Webhook() *aquagram.SetWebhookParams {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.webhook
+}
+
+// PendingUpdates returns the updates not confirmed by the bot yet.
+func (server *Server) PendingUpdates() []*aquagram.Update {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]*aquagram.Update(nil), server.updates...)
+}
+
+/*
+PushUpdate assigns an update_id to update and delivers it to the bot.
+
+When a webhook is set, the update is sent to it and an error is returned
+if the webhook does not answer with a 2xx status code.
+Otherwise the update is queued until the bot calls getUpdates.
+*/
+func (server *Server) PushUpdate(update *aquagram.Update) error {
+	server.mu.Lock()
+
+	server.lastUpdateID++
+	update.UpdateID = server.lastUpdateID
+
+	webhook := server.webhook
+
+	if webhook == nil {
+		server.updates = append(server.updates, update)
+
+		close(server.updatesSignal)
+		server.updatesSignal = make(chan struct{})
+	}
+
+	server.mu.Unlock()
+
+	if webhook == nil {
+		return nil
+	}
+
+	return server.deliver(webhook, update)
+}
+
+// NextUpdateID returns a new update_id, for updates dispatched without [Server.PushUpdate].
+func (server *Server) NextUpdateID() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.lastUpdateID++
+	return server.lastUpdateID
+}
+
+func (server *Server) deliver(webhook *aquagram.SetWebhookParams, update *aquagram.Update) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if webhook.SecretToken != aquagram.EmptyString {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", webhook.SecretToken)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered with status %s", res.Status)
+	}
+
+	return nil
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || !strings.HasPrefix(r.URL.Path, "/bot") {
+		writeError(w, NewError(404, "Not Found"))
+		return
+	}
+
+	if token != server.Token {
+		writeError(w, NewError(401, "Unauthorized"))
+		return
+	}
+
+	request, err := parseRequest(r)
+	if err != nil {
+		writeError(w, NewError(400, "Bad Request: "+err.Error()))
+		return
+	}
+
+	request.Method = method
+	request.ctx = r.Context()
+
+	server.mu.Lock()
+	server.requests = append(server.requests, request)
+	handler, ok := server.handlers[method]
+
+	if !ok {
+		// method names are case-insensitive
+		for name, h := range server.handlers {
+			if strings.EqualFold(name, method) {
+				handler, ok = h, true
+				break
+			}
+		}
+	}
+
+	server.mu.Unlock()
+
+	if !ok {
+		writeError(w, NewError(404, "Not Found"))
+		return
+	}
+
+	result, err := handler(server, request)
+
+	server.mu.Lock()
+	request.Result = result
+	request.Err = err
+	server.mu.Unlock()
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeResult(w, result)
+}
+
+// NewError returns an error answered as "ok": false by the server.
+func NewError(code int, description string) *aquagram.APIError {
+	return &aquagram.APIError{
+		ErrorCode:   code,
+		Description: description,
+	}
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"ok":     true,
+		"result": result,
+	})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *aquagram.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = NewError(500, "Internal Server Error: "+err.Error())
+	}
+
+	res := aquagram.TelegramResponse{
+		ErrorCode:   apiErr.ErrorCode,
+		Description: apiErr.Description,
+	}
+
+	if apiErr.RetryAfter > 0 || apiErr.MigrateToChatID != 0 {
+		res.Parameters = &aquagram.ResponseParameters{
+			RetryAfter:      int64(apiErr.RetryAfter.Seconds()),
+			MigrateToChatID: apiErr.MigrateToChatID,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.ErrorCode)
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func parseRequest(r *http.Request) (*Request, error) {
+	request := new(Request)
+	request.Params = make(map[string]string)
+	request.Files = make(map[string]*File)
+	request.Time = time.Now()
+
+	contentType := r.Header.Get("Content-Type")
+
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		var params map[string]json.RawMessage
+
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null" {
+			break
+		}
+
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, err
+		}
+
+		for key, value := range params {
+			var str string
+
+			if json.Unmarshal(value, &str) == nil {
+				request.Params[key] = str
+			} else if string(value) != "null" {
+				request.Params[key] = string(value)
+			}
+		}
+
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+
+		for key, values := range r.MultipartForm.Value {
+			request.Params[key] = values[0]
+		}
+
+		for key, headers := range r.MultipartForm.File {
+			if err := request.readFile(key, headers[0]); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		for key, values := range r.Form {
+			request.Params[key] = values[0]
+		}
+	}
+
+	return request, nil
+}
+
+func (request *Request) readFile(key string, header *multipart.FileHeader) error {
+	f, err := header.Open()
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	file := new(File)
+	file.FileName = header.Filename
+	file.Data = data
+
+	request.Files[key] = file
+
+	return nil
+}
+
+// PrivateChat returns the private chat of a user with the bot.
+func PrivateChat(user *aquagram.User) *aquagram.Chat {
+	return &aquagram.Chat{
+		ID:        user.ID,
+		Type:      aquagram.ChatTypePrivate,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
@@ -0,0 +1,171 @@
+package aquagramtest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestGetMe(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	me, err := bot.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if me.ID != server.Me.ID {
+		t.Errorf("unexpected user %+v", me)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := aquagram.NewBot("wrong-token")
+	bot.Config.API = server.URL()
+
+	if _, err := bot.GetMe(); !errors.Is(err, aquagram.ErrTgUnauthorized) {
+		t.Errorf("expected ErrTgUnauthorized, got %v", err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	if _, err := bot.SendMessage("42", "hello", nil); !errors.Is(err, aquagram.ErrTgChatNotFound) {
+		t.Errorf("expected ErrTgChatNotFound, got %v", err)
+	}
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+	server.AddChat(aquagramtest.PrivateChat(user))
+
+	message, err := bot.SendMessage("42", "hello", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Text != "hello" || message.Bot != bot {
+		t.Errorf("unexpected message %+v", message)
+	}
+
+	if _, err := message.EditText("hello", nil); !errors.Is(err, aquagram.ErrTgMessageNotModified) {
+		t.Errorf("expected ErrTgMessageNotModified, got %v", err)
+	}
+
+	sent := server.SentMessages(42)
+	if len(sent) != 1 || sent[0].Text != "hello" {
+		t.Errorf("unexpected sent messages %+v", sent)
+	}
+}
+
+func TestSendDocument(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	server.AddChat(&aquagram.Chat{ID: -100, Type: aquagram.ChatTypeSuperGroup, Title: "group"})
+
+	bot := server.NewBot()
+
+	document := aquagram.InputFileFromReader(strings.NewReader("content"))
+	document.FileName = "sample.txt"
+
+	message, err := bot.SendDocument("-100", document, &aquagram.SendDocumentParams{Caption: "caption"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Document == nil || message.Caption != "caption" {
+		t.Fatalf("unexpected message %+v", message)
+	}
+
+	file := server.File(message.Document.FileID)
+	if file == nil || string(file.Data) != "content" || file.FileName != "sample.txt" {
+		t.Errorf("unexpected file %+v", file)
+	}
+}
+
+func TestPolling(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	bot.OnCommand("start", func(bot *aquagram.Bot, message *aquagram.Message) error {
+		_, err := message.Reply("welcome", nil)
+		return err
+	})
+
+	go bot.StartPolling(false)
+	defer bot.Stop()
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+
+	if _, err := server.InjectMessage(user, aquagramtest.PrivateChat(user), "/start"); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for len(server.SentMessages(user.ID)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the bot did not reply")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reply := server.SentMessages(user.ID)[0]
+	if reply.Text != "welcome" || reply.ReplyToMessage == nil {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+}
+
+func TestWebhookConflict(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	if err := bot.SetWebhook("https://example.com/webhook", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := bot.GetUpdates(bot.Context(), new(aquagram.PollingOptions))
+	if !errors.Is(err, aquagram.ErrTgConflict) {
+		t.Errorf("expected ErrTgConflict, got %v", err)
+	}
+}
+
+func TestChatMembers(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	server.AddChat(&aquagram.Chat{ID: -100, Type: aquagram.ChatTypeSuperGroup, Title: "group"})
+
+	bot := server.NewBot()
+
+	if err := bot.BanChatMember(-100, 42, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := bot.GetChatMember("-100", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !member.IsKicked() {
+		t.Errorf("member should be kicked, got %s", member.Status)
+	}
+}
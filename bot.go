@@ -2,6 +2,9 @@ package aquagram
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 )
 
 type Bot struct {
@@ -19,6 +22,22 @@ type Bot struct {
 	stopContext context.Context
 	stopFunc    context.CancelFunc
 
+	// cancelled by Shutdown to stop receiving updates
+	updaterContext context.Context
+	updaterStop    context.CancelFunc
+
+	updaters sync.WaitGroup
+	inFlight sync.WaitGroup
+
+	// set by Shutdown, no updater or update is added to the wait groups after
+	shutdownMu   sync.Mutex
+	shuttingDown bool
+
+	// *webhookReply by Event, see WebhookReply
+	webhookReplies sync.Map
+
+	// Deprecated: it is never updated, duplicated updates
+	// are discarded with [Config.Dedup].
 	LastUpdateID int
 }
 
@@ -30,6 +49,7 @@ func NewBot(token string) *Bot {
 	bot.handlers = make(Handlers)
 
 	bot.stopContext, bot.stopFunc = context.WithCancel(context.Background())
+	bot.updaterContext, bot.updaterStop = context.WithCancel(bot.stopContext)
 
 	return bot
 }
@@ -39,20 +59,30 @@ func (bot *Bot) Context() context.Context {
 }
 
 func (bot *Bot) start() error {
+	if err := bot.connect(); err != nil {
+		return err
+	}
+
+	bot.started()
+
+	return nil
+}
+
+// connect checks the token of the bot, setting [Bot.Me].
+func (bot *Bot) connect() error {
 	if bot.token == EmptyString {
 		return ErrEmptyToken
 	}
 
 	_, err := bot.GetMe()
-	if err != nil {
-		return err
-	}
+	return err
+}
 
+// started calls [Config.OnStartFunc].
+func (bot *Bot) started() {
 	if bot.Config.OnStartFunc != nil {
 		bot.Config.OnStartFunc(bot)
 	}
-
-	return nil
 }
 
 func (bot *Bot) StartPolling(dropPendingUpdates bool) error {
@@ -69,26 +99,158 @@ func (bot *Bot) StartPollingWithOptions(options *PollingOptions) error {
 
 	updater := NewPollingUpdater(bot)
 	updater.Options = options
-	updater.Start()
 
-	return nil
+	return bot.runUpdater(updater)
 }
 
 func (bot *Bot) StartWebhook(addr string, secretToken string) error {
+	options := &WebhookOptions{
+		Addr:        addr,
+		SecretToken: secretToken,
+	}
+
+	return bot.StartWebhookWithOptions(options)
+}
+
+/*
+[StartWebhookWithOptions] serves a webhook receiving the updates,
+registering it with SetWebhook when options.URL is set:
+
+	bot.StartWebhookWithOptions(&aquagram.WebhookOptions{
+		Addr:        ":8443",
+		URL:         "https://example.com:8443",
+		Path:        "/telegram",
+		SecretToken: secret,
+		CertFile:    "cert.pem",
+		KeyFile:     "key.pem",
+	})
+
+The webhook is deleted on [Bot.Shutdown].
+*/
+func (bot *Bot) StartWebhookWithOptions(options *WebhookOptions) error {
 	if err := bot.start(); err != nil {
 		return err
 	}
 
 	updater := NewWebhookUpdater(bot)
-	updater.secretToken = secretToken
+	updater.Options = options
 
-	return updater.Start(addr)
+	return bot.runUpdater(updater)
 }
 
+// Stop cancels the bot context immediately, interrupting the running handlers
+// and API requests, see [Bot.Shutdown] to stop gracefully.
 func (bot *Bot) Stop() {
 	bot.stopFunc()
 }
 
+/*
+[Shutdown] gracefully stops the bot: it stops the updaters, closing the webhook server
+or confirming the last received update to Telegram, waits for the running handlers
+and finally cancels the bot context.
+
+Updates received after Shutdown is called are not handled,
+and updaters started after it return immediately.
+
+If ctx expires before the handlers finish, the bot context is cancelled anyway
+and the error of ctx is returned.
+*/
+func (bot *Bot) Shutdown(ctx context.Context) error {
+	bot.shutdownMu.Lock()
+	bot.shuttingDown = true
+	bot.shutdownMu.Unlock()
+
+	bot.updaterStop()
+
+	err := waitContext(ctx, &bot.updaters)
+	if err == nil {
+		err = waitContext(ctx, &bot.inFlight)
+	}
+
+	bot.stopFunc()
+
+	return err
+}
+
+// add adds one to wg, it returns false once the bot is shutting down.
+func (bot *Bot) add(wg *sync.WaitGroup) bool {
+	bot.shutdownMu.Lock()
+	defer bot.shutdownMu.Unlock()
+
+	if bot.shuttingDown {
+		return false
+	}
+
+	wg.Add(1)
+	return true
+}
+
+// dispatch passes update to the dispatcher of the bot, it is an [UpdateSink].
+func (bot *Bot) dispatch(update *Update, done func()) error {
+	window := bot.Config.Dedup
+
+	if window == nil || update.UpdateID == 0 {
+		bot.updateReceived(update)
+
+		return bot.dispatcher().dispatch(bot, update, func(handled bool) {
+			if done != nil {
+				done()
+			}
+		})
+	}
+
+	if window.begin(update.UpdateID) {
+		bot.updateDropped(DropReasonDuplicate)
+
+		if done != nil {
+			done()
+		}
+
+		return ErrDuplicateUpdate
+	}
+
+	// the update is remembered only once handled, so it is received
+	// again if the bot crashes before, or if it was dropped
+	ack := func(handled bool) {
+		if !handled {
+			window.release(update.UpdateID)
+
+		} else if err := window.finish(update.UpdateID); err != nil && bot.Config.OnErrorFunc != nil {
+			bot.Config.OnErrorFunc(bot, fmt.Errorf("%w: saving seen updates: %w", ErrUpdaterError, err))
+		}
+
+		if done != nil {
+			done()
+		}
+	}
+
+	bot.updateReceived(update)
+
+	err := bot.dispatcher().dispatch(bot, update, ack)
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrDispatcherClosed) {
+		window.release(update.UpdateID)
+	}
+
+	return err
+}
+
+func waitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 /*
 A simple method for testing your bot's authentication token.
 
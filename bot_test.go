@@ -3,13 +3,55 @@ package aquagram_test
 import (
 	"net/http"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
 )
 
+type testEnv struct {
+	bot    *aquagram.Bot
+	chatID string
+	userID int64
+}
+
+/*
+newTestEnv uses the Bot API when the TOKEN environment variable is set,
+with CHAT_ID and USER_ID as the chat and the member to test.
+
+Otherwise an offline fake server is started.
+*/
+func newTestEnv(t *testing.T) *testEnv {
+	env := new(testEnv)
+
+	if token := os.Getenv("TOKEN"); token != aquagram.EmptyString {
+		env.bot = aquagram.NewBot(token)
+		env.chatID = os.Getenv("CHAT_ID")
+		env.userID, _ = strconv.ParseInt(os.Getenv("USER_ID"), 10, 64)
+
+		return env
+	}
+
+	server := aquagramtest.NewServer()
+	t.Cleanup(server.Close)
+
+	chat := &aquagram.Chat{ID: -1001, Type: aquagram.ChatTypeSuperGroup, Title: "aquagram"}
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+
+	server.AddChat(chat)
+	server.SetChatMember(chat.ID, &aquagram.ChatMember{Status: aquagram.ChatMemberStatusCreator, User: user})
+	server.SetChatMember(chat.ID, &aquagram.ChatMember{Status: aquagram.ChatMemberStatusAdministrator, User: server.Me})
+
+	env.bot = server.NewBot()
+	env.chatID = aquagram.ChatID(chat.ID)
+	env.userID = user.ID
+
+	return env
+}
+
 func TestGetMe(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
+	bot := newTestEnv(t).bot
 
 	me, err := bot.GetMe()
 	if err != nil {
@@ -23,7 +65,7 @@ func TestGetMe(t *testing.T) {
 }
 
 func TestGetMyName(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
+	bot := newTestEnv(t).bot
 
 	botName, err := bot.GetMyName("")
 	if err != nil {
@@ -37,7 +79,7 @@ func TestGetMyName(t *testing.T) {
 }
 
 func TestSetMyName(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
+	bot := newTestEnv(t).bot
 
 	botName, err := bot.GetMyName("")
 	if err != nil {
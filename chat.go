@@ -12,6 +12,9 @@ const (
 	ChatTypeGroup      ChatType = "group"
 	ChatTypeSuperGroup ChatType = "supergroup"
 	ChatTypeChannel    ChatType = "channel"
+
+	// only in InlineQuery, for a private chat with the sender of the query
+	ChatTypeSender ChatType = "sender"
 )
 
 type Chat struct {
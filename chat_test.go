@@ -1,17 +1,13 @@
 package aquagram_test
 
 import (
-	"os"
-	"strconv"
 	"testing"
-
-	"github.com/aquagram/aquagram"
 )
 
 func TestGetChatAdministrators(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
+	env := newTestEnv(t)
 
-	admins, err := bot.GetChatAdministrators(os.Getenv("CHAT_ID"))
+	admins, err := env.bot.GetChatAdministrators(env.chatID)
 	if err != nil {
 		t.Error(err)
 		return
@@ -23,9 +19,9 @@ func TestGetChatAdministrators(t *testing.T) {
 }
 
 func TestGetChatMemberCount(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
+	env := newTestEnv(t)
 
-	count, err := bot.GetChatMemberCount(os.Getenv("CHAT_ID"))
+	count, err := env.bot.GetChatMemberCount(env.chatID)
 	if err != nil {
 		t.Error(err)
 		return
@@ -37,12 +33,9 @@ func TestGetChatMemberCount(t *testing.T) {
 }
 
 func TestGetChatMember(t *testing.T) {
-	bot := aquagram.NewBot(os.Getenv("TOKEN"))
-
-	userIDRaw := os.Getenv("USER_ID")
-	userID, _ := strconv.ParseInt(userIDRaw, 10, 64)
+	env := newTestEnv(t)
 
-	member, err := bot.GetChatMember(os.Getenv("CHAT_ID"), userID)
+	member, err := env.bot.GetChatMember(env.chatID, env.userID)
 	if err != nil {
 		t.Error(err)
 		return
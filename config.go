@@ -1,14 +1,16 @@
 package aquagram
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 )
 
 type (
-	ErrorFunc func(bot *Bot, err error)
-	StartFunc func(bot *Bot)
+	ErrorFunc    func(bot *Bot, err error)
+	StartFunc    func(bot *Bot)
+	RequestFunc  func(bot *Bot, request *APIRequest) error
+	ResponseFunc func(bot *Bot, response *APIResponse)
 )
 
 type Config struct {
@@ -21,23 +23,74 @@ type Config struct {
 	// HTTP Client using to perform API requests
 	Client *http.Client
 
+	// Transport used to send API requests.
+	//
+	// By default is nil, requests are sent with an [HTTPTransport]
+	// built from API and Client.
+	Transport Transport
+
+	// Dispatcher running the handlers of the received updates,
+	// it can be shared by many bots, see [NewDispatcher].
+	//
+	// By default is nil, every update is handled in a new goroutine.
+	Dispatcher *Dispatcher
+
+	// Window used to discard the updates received more than once,
+	// it must not be shared by many bots.
+	//
+	// An update is remembered once its handlers finished, so a persistent
+	// window does not discard the updates received again after a crash,
+	// see [PollingOptions.OffsetStore].
+	//
+	// By default remembers the last [DefaultDedupSize] updates in memory.
+	Dedup *DedupWindow
+
 	// ParseMode that the bot will use wherever
 	// necessary unless specified otherwise.
 	//
 	// NOTE: It is not fully implemented yet
 	DefaultParseMode ParseMode
 
-	Logger *log.Logger
+	// Limiter used to delay outgoing messages, see [NewRateLimiter].
+	//
+	// By default is nil, requests are never delayed.
+	Limiter Limiter
+
+	// Logger used to emit structured records about updates and API requests,
+	// use [LoggerFromStd] to log through a [log.Logger].
+	//
+	// By default is [slog.Default].
+	Logger *slog.Logger
+
+	// Metrics collecting API requests and updates, see [NewPrometheusMetrics].
+	//
+	// By default is nil, nothing is measured.
+	Metrics Metrics
 
 	// Function called when an error occurs in the bot
 	OnErrorFunc ErrorFunc
 
+	// Functions called before every API request, in order.
+	//
+	// They can modify the request, e.g. with [APIRequest.Set],
+	// or cancel it returning an error.
+	OnRequestFuncs []RequestFunc
+
+	// Functions called after every API request, in order.
+	OnResponseFuncs []ResponseFunc
+
 	// Function called when the bot is started
 	//
 	// It is called after [GetMe] execution,
 	// before starting the updater
 	OnStartFunc StartFunc
 
+	// Policy used to repeat failed API requests,
+	// see [DefaultRetryPolicy].
+	//
+	// By default is nil, requests are not retried.
+	RetryPolicy *RetryPolicy
+
 	// Time to wait between errors.
 	//
 	// By default is 1s
@@ -49,13 +102,13 @@ func DefaultConfig() *Config {
 
 	config.API = "https://api.telegram.org"
 	config.Client = new(http.Client)
+	config.Dedup = NewDedupWindow(DefaultDedupSize)
 	config.DefaultParseMode = ParseModeDisabled
 
-	config.Logger = log.Default()
-	config.Logger.SetPrefix("[aquagram]: ")
+	config.Logger = slog.Default().With(slog.String("logger", "aquagram"))
 
 	config.OnErrorFunc = func(bot *Bot, err error) {
-		bot.Config.Logger.Println(err)
+		bot.Config.Logger.Error("bot error", slog.Any("error", err))
 	}
 
 	config.RetriesInterval = time.Second
@@ -0,0 +1,253 @@
+package aquagram
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// DefaultDedupSize is the number of update identifiers remembered by default, see [Config.Dedup].
+const DefaultDedupSize = 1000
+
+/*
+[DedupStore] persists the identifiers remembered by a [DedupWindow],
+see [NewFileDedupStore].
+*/
+type DedupStore interface {
+	// Load returns the saved identifiers, from the oldest to the newest.
+	Load() ([]int, error)
+
+	Save(ids []int) error
+}
+
+/*
+[DedupWindow] remembers the identifiers of the last handled updates,
+to discard the updates delivered more than once, e.g. when Telegram
+retries a webhook request that timed out.
+
+A bot remembers an update only once its handlers finished, so an update
+received again while it is being handled is discarded too, but an update
+not handled before a crash is not.
+*/
+type DedupWindow struct {
+	mu   sync.Mutex
+	size int
+
+	// ring buffer of the remembered identifiers, from the oldest
+	ids  []int
+	next int
+	set  map[int]struct{}
+
+	// updates being handled, not remembered yet
+	pending map[int]struct{}
+
+	store DedupStore
+
+	// incremented on every change, saved is the last one in the store
+	version int
+
+	// held while saving, outside mu so the dispatch does not wait for the store
+	saveMu sync.Mutex
+	saved  int
+}
+
+func NewDedupWindow(size int) *DedupWindow {
+	if size <= 0 {
+		size = DefaultDedupSize
+	}
+
+	window := new(DedupWindow)
+	window.size = size
+	window.ids = make([]int, 0, size)
+	window.set = make(map[int]struct{}, size)
+	window.pending = make(map[int]struct{})
+
+	return window
+}
+
+/*
+[NewPersistentDedupWindow] returns a [DedupWindow] saving the identifiers in store,
+so the duplicates are discarded after a restart too.
+*/
+func NewPersistentDedupWindow(size int, store DedupStore) (*DedupWindow, error) {
+	window := NewDedupWindow(size)
+
+	ids, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		window.add(id)
+	}
+
+	window.store = store
+
+	return window, nil
+}
+
+/*
+[Seen] reports whether id was already seen, otherwise it is remembered,
+forgetting the oldest identifier if the window is full.
+*/
+func (window *DedupWindow) Seen(id int) (bool, error) {
+	window.mu.Lock()
+
+	if window.containsLocked(id) {
+		window.mu.Unlock()
+		return true, nil
+	}
+
+	version := window.remember(id)
+	window.mu.Unlock()
+
+	return false, window.save(version)
+}
+
+// begin reports whether id was already seen, otherwise it is marked as being handled.
+func (window *DedupWindow) begin(id int) bool {
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	if window.containsLocked(id) {
+		return true
+	}
+
+	window.pending[id] = struct{}{}
+
+	return false
+}
+
+// finish remembers id once its update was handled.
+func (window *DedupWindow) finish(id int) error {
+	window.mu.Lock()
+
+	delete(window.pending, id)
+
+	if _, ok := window.set[id]; ok {
+		window.mu.Unlock()
+		return nil
+	}
+
+	version := window.remember(id)
+	window.mu.Unlock()
+
+	return window.save(version)
+}
+
+// release forgets id without remembering it, its update was not handled.
+func (window *DedupWindow) release(id int) {
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	delete(window.pending, id)
+}
+
+func (window *DedupWindow) containsLocked(id int) bool {
+	if _, ok := window.pending[id]; ok {
+		return true
+	}
+
+	_, ok := window.set[id]
+	return ok
+}
+
+// remember adds id and returns the version of the window to save.
+func (window *DedupWindow) remember(id int) int {
+	window.add(id)
+	window.version++
+
+	return window.version
+}
+
+/*
+save saves the window in the store, if version was not saved yet.
+
+The saves of concurrent updates are merged, the first one to take
+saveMu saves the latest identifiers.
+*/
+func (window *DedupWindow) save(version int) error {
+	if window.store == nil {
+		return nil
+	}
+
+	window.saveMu.Lock()
+	defer window.saveMu.Unlock()
+
+	if window.saved >= version {
+		return nil
+	}
+
+	window.mu.Lock()
+	ids := window.snapshot()
+	latest := window.version
+	window.mu.Unlock()
+
+	if err := window.store.Save(ids); err != nil {
+		return err
+	}
+
+	window.saved = latest
+
+	return nil
+}
+
+func (window *DedupWindow) add(id int) {
+	if len(window.ids) < window.size {
+		window.ids = append(window.ids, id)
+		window.set[id] = struct{}{}
+		return
+	}
+
+	delete(window.set, window.ids[window.next])
+
+	window.ids[window.next] = id
+	window.set[id] = struct{}{}
+	window.next = (window.next + 1) % window.size
+}
+
+// snapshot returns the identifiers from the oldest to the newest.
+func (window *DedupWindow) snapshot() []int {
+	ids := make([]int, 0, len(window.ids))
+	ids = append(ids, window.ids[window.next:]...)
+	ids = append(ids, window.ids[:window.next]...)
+
+	return ids
+}
+
+// FileDedupStore saves the identifiers in a JSON file.
+type FileDedupStore struct {
+	Path string
+}
+
+func NewFileDedupStore(path string) *FileDedupStore {
+	store := new(FileDedupStore)
+	store.Path = path
+
+	return store
+}
+
+func (store *FileDedupStore) Load() ([]int, error) {
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	return ids, json.Unmarshal(data, &ids)
+}
+
+func (store *FileDedupStore) Save(ids []int) error {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(store.Path, data)
+}
@@ -0,0 +1,213 @@
+package aquagram_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+func TestDedupWindow(t *testing.T) {
+	store := aquagram.NewFileDedupStore(filepath.Join(t.TempDir(), "seen.json"))
+
+	window, err := aquagram.NewPersistentDedupWindow(2, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int{1, 2, 3} {
+		if seen, err := window.Seen(id); seen || err != nil {
+			t.Fatalf("update %d: seen %t, error %v", id, seen, err)
+		}
+	}
+
+	if seen, _ := window.Seen(3); !seen {
+		t.Error("update 3 was not remembered")
+	}
+
+	// restarted, the oldest update was forgotten
+	window, err = aquagram.NewPersistentDedupWindow(2, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, _ := window.Seen(2); !seen {
+		t.Error("update 2 was not persisted")
+	}
+
+	if seen, _ := window.Seen(1); seen {
+		t.Error("update 1 should be forgotten")
+	}
+}
+
+func TestDedupUpdates(t *testing.T) {
+	bot := aquagram.NewBot("token")
+
+	var handled atomic.Int32
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		handled.Add(1)
+		return nil
+	})
+
+	server := httptest.NewServer(aquagram.NewWebhookUpdater(bot))
+	defer server.Close()
+
+	body := `{"update_id":1,"message":{"message_id":1,"chat":{"id":42},"text":"hello"}}`
+
+	// Telegram retries the request
+	for i := 0; i < 2; i++ {
+		res, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", res.StatusCode)
+		}
+	}
+
+	bot.Shutdown(context.Background())
+
+	if handled.Load() != 1 {
+		t.Errorf("the update was handled %d times", handled.Load())
+	}
+}
+
+func TestDedupHandledUpdates(t *testing.T) {
+	store := aquagram.NewFileDedupStore(filepath.Join(t.TempDir(), "seen.json"))
+
+	window, err := aquagram.NewPersistentDedupWindow(10, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot := aquagram.NewBot("token")
+	bot.Config.Dedup = window
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	server := httptest.NewServer(aquagram.NewWebhookUpdater(bot))
+	defer server.Close()
+
+	postUpdate(t, server.URL, 1)
+	<-started
+
+	// retried while it is being handled
+	postUpdate(t, server.URL, 1)
+
+	// crashed before the handler finished, the update must be received again
+	restarted, err := aquagram.NewPersistentDedupWindow(10, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, _ := restarted.Seen(1); seen {
+		t.Error("the update was remembered before it was handled")
+	}
+
+	close(release)
+	bot.Shutdown(context.Background())
+
+	if len(started) != 0 {
+		t.Error("the update was handled twice")
+	}
+
+	restarted, err = aquagram.NewPersistentDedupWindow(10, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seen, _ := restarted.Seen(1); !seen {
+		t.Error("the handled update was not remembered")
+	}
+}
+
+type blockingDedupStore struct {
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (store *blockingDedupStore) Load() ([]int, error) {
+	return nil, nil
+}
+
+func (store *blockingDedupStore) Save(ids []int) error {
+	store.saving <- struct{}{}
+	<-store.release
+	return nil
+}
+
+func TestDedupSaveOutsideLock(t *testing.T) {
+	store := &blockingDedupStore{saving: make(chan struct{}), release: make(chan struct{})}
+
+	window, err := aquagram.NewPersistentDedupWindow(10, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := make(chan error)
+
+	go func() {
+		_, err := window.Seen(1)
+		saved <- err
+	}()
+
+	<-store.saving
+
+	// the window is not locked while saving
+	checked := make(chan bool)
+
+	go func() {
+		seen, _ := window.Seen(1)
+		checked <- seen
+	}()
+
+	select {
+	case seen := <-checked:
+		if !seen {
+			t.Error("update 1 was not remembered")
+		}
+	case <-time.After(time.Second):
+		t.Error("the window was locked while saving")
+	}
+
+	close(store.release)
+
+	if err := <-saved; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDedupDroppedUpdates(t *testing.T) {
+	bot, _, handled := newDispatcherBot(t, aquagram.QueueReject)
+
+	server := httptest.NewServer(aquagram.NewWebhookUpdater(bot))
+	defer server.Close()
+
+	postUpdate(t, server.URL, 1)
+	<-handled
+	postUpdate(t, server.URL, 2)
+
+	// dropped twice, the redelivery is not discarded as a duplicate
+	for i := 0; i < 2; i++ {
+		if status := postUpdate(t, server.URL, 3); status != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", status)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package aquagram
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// QueuePolicy decides what a [Dispatcher] does when its queue is full.
+type QueuePolicy int
+
+const (
+	// Wait until a worker takes an update from the queue.
+	QueueBlock QueuePolicy = iota
+
+	// Drop the oldest queued update to make room for the new one.
+	//
+	// With Order and Workers, the queue of a worker is shared by many chats
+	// or users, so the dropped update can be of another chat or user.
+	QueueDropOldest
+
+	// Drop the new update, [Dispatcher.Dispatch] returns [ErrQueueFull].
+	QueueReject
+)
+
+// DispatchOrder decides which updates a [Dispatcher] handles one after another.
+type DispatchOrder int
+
+const (
+	// Updates are handled concurrently, in any order.
+	OrderNone DispatchOrder = iota
+
+	// Updates of the same chat are handled one after another,
+	// in the order they are received.
+	OrderByChat
+
+	// Updates of the same user are handled one after another,
+	// in the order they are received.
+	OrderByUser
+)
+
+type DispatcherOptions struct {
+	// Number of goroutines handling the updates.
+	//
+	// By default is 0, every update is handled in a new goroutine.
+	Workers int
+
+	// Number of updates waiting for a free worker,
+	// with Order it is split among the workers.
+	// Without Workers, it is the number of updates waiting in each chat or user.
+	//
+	// By default is 100
+	QueueSize int
+
+	// What to do when the queue is full, by default is [QueueBlock].
+	Policy QueuePolicy
+
+	// Updates handled one after another, by default is [OrderNone].
+	//
+	// Updates without chat or user are handled in any order.
+	//
+	// With Workers, every chat or user is always handled by the same worker,
+	// so a slow handler also delays the other chats or users of its worker.
+	// Without Workers, every chat or user has its own goroutine.
+	Order DispatchOrder
+}
+
+/*
+[Dispatcher] runs the handlers of the received updates,
+limiting how many of them run at the same time.
+
+A dispatcher can be shared by many bots:
+
+	dispatcher := aquagram.NewDispatcher(&aquagram.DispatcherOptions{
+		Workers:   8,
+		QueueSize: 1000,
+		Policy:    aquagram.QueueReject,
+	})
+
+	bot.Config.Dispatcher = dispatcher
+	other.Config.Dispatcher = dispatcher
+
+Dropped updates are reported to [Config.Metrics] of their bot.
+
+Once the dispatcher is no longer needed, [Dispatcher.Close] stops its workers.
+*/
+type Dispatcher struct {
+	Options *DispatcherOptions
+
+	start sync.Once
+	close sync.Once
+
+	// closed by Close, wakes up the blocked dispatches
+	stop chan struct{}
+
+	// held to send to the queues, so they are not closed meanwhile
+	closeMu sync.RWMutex
+	closed  bool
+
+	// with Order, every worker has its own queue
+	queues []chan *dispatchJob
+
+	// updates waiting in the same lane, used without workers
+	mu    sync.Mutex
+	lanes map[int64]*dispatchLane
+}
+
+type dispatchLane struct {
+	jobs []*dispatchJob
+
+	// closed when a job is taken from the lane
+	space chan struct{}
+}
+
+type dispatchJob struct {
+	bot    *Bot
+	update *Update
+
+	// ack is called once the update is handled or dropped, reporting which one
+	ack func(handled bool)
+}
+
+func NewDispatcher(options *DispatcherOptions) *Dispatcher {
+	if options == nil {
+		options = new(DispatcherOptions)
+	}
+
+	dispatcher := new(Dispatcher)
+	dispatcher.Options = options
+	dispatcher.stop = make(chan struct{})
+
+	return dispatcher
+}
+
+// defaultDispatcher is used by the bots without [Config.Dispatcher].
+var defaultDispatcher = NewDispatcher(nil)
+
+func (bot *Bot) dispatcher() *Dispatcher {
+	if bot.Config.Dispatcher != nil {
+		return bot.Config.Dispatcher
+	}
+
+	return defaultDispatcher
+}
+
+/*
+[Dispatch] queues update to be handled by bot.
+
+With the [QueueBlock] policy it waits until there is room in the queue
+or the bot is stopped.
+
+After [Dispatcher.Close] it returns [ErrDispatcherClosed].
+*/
+func (dispatcher *Dispatcher) Dispatch(bot *Bot, update *Update) error {
+	return dispatcher.dispatch(bot, update, nil)
+}
+
+/*
+dispatch queues update and calls done once it is handled or dropped,
+handled is false if it was dropped.
+
+If the bot or the dispatcher is stopped before the update is queued,
+done is not called and [context.Canceled] or [ErrDispatcherClosed] is returned.
+*/
+func (dispatcher *Dispatcher) dispatch(bot *Bot, update *Update, done func(handled bool)) error {
+	dispatcher.closeMu.RLock()
+	defer dispatcher.closeMu.RUnlock()
+
+	if dispatcher.closed {
+		return ErrDispatcherClosed
+	}
+
+	// the bot is shutting down, it does not wait for new updates
+	if !bot.add(&bot.inFlight) {
+		return context.Canceled
+	}
+
+	job := &dispatchJob{
+		bot:    bot,
+		update: update,
+		ack:    done,
+	}
+
+	key, ordered := dispatcher.key(update)
+
+	if dispatcher.Options.Workers <= 0 {
+		if ordered {
+			return dispatcher.runLane(key, job)
+		}
+
+		go job.run()
+		return nil
+	}
+
+	dispatcher.start.Do(dispatcher.startWorkers)
+
+	queue := dispatcher.queues[0]
+
+	if ordered && len(dispatcher.queues) > 1 {
+		queue = dispatcher.queues[uint64(key)%uint64(len(dispatcher.queues))]
+
+	} else if len(dispatcher.queues) > 1 {
+		queue = dispatcher.queues[uint(update.UpdateID)%uint(len(dispatcher.queues))]
+	}
+
+	switch dispatcher.Options.Policy {
+	case QueueDropOldest:
+		for {
+			select {
+			case queue <- job:
+				return nil
+			default:
+			}
+
+			select {
+			case oldest := <-queue:
+				oldest.drop(DropReasonQueueFull)
+			default:
+			}
+		}
+
+	case QueueReject:
+		select {
+		case queue <- job:
+			return nil
+		default:
+			job.drop(DropReasonQueueFull)
+			return ErrQueueFull
+		}
+
+	default:
+		select {
+		case queue <- job:
+			return nil
+		case <-bot.stopContext.Done():
+			// never handled, so not acknowledged
+			bot.inFlight.Done()
+			return bot.stopContext.Err()
+		case <-dispatcher.stop:
+			bot.inFlight.Done()
+			return ErrDispatcherClosed
+		}
+	}
+}
+
+/*
+[Close] stops the workers once the queued updates are handled,
+later updates are rejected with [ErrDispatcherClosed].
+
+It does not wait for the handlers, see [Bot.Shutdown].
+*/
+func (dispatcher *Dispatcher) Close() {
+	dispatcher.close.Do(func() {
+		close(dispatcher.stop)
+
+		dispatcher.closeMu.Lock()
+		defer dispatcher.closeMu.Unlock()
+
+		dispatcher.closed = true
+
+		for _, queue := range dispatcher.queues {
+			close(queue)
+		}
+	})
+}
+
+// key returns the key of the lane of update, if it must be ordered.
+func (dispatcher *Dispatcher) key(update *Update) (int64, bool) {
+	event := update.event()
+	if event == nil {
+		return 0, false
+	}
+
+	switch dispatcher.Options.Order {
+	case OrderByChat:
+		if chat := event.GetChat(); chat != nil {
+			return chat.ID, true
+		}
+
+	case OrderByUser:
+		if user := event.GetFrom(); user != nil {
+			return user.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+func (dispatcher *Dispatcher) queueSize() int {
+	if dispatcher.Options.QueueSize <= 0 {
+		return 100
+	}
+
+	return dispatcher.Options.QueueSize
+}
+
+func (dispatcher *Dispatcher) startWorkers() {
+	workers := dispatcher.Options.Workers
+	size := dispatcher.queueSize()
+
+	if dispatcher.Options.Order == OrderNone {
+		queue := make(chan *dispatchJob, size)
+		dispatcher.queues = []chan *dispatchJob{queue}
+
+		for i := 0; i < workers; i++ {
+			go work(queue)
+		}
+
+		return
+	}
+
+	// the queue size is split among the workers
+	dispatcher.queues = make([]chan *dispatchJob, workers)
+
+	for i := range dispatcher.queues {
+		dispatcher.queues[i] = make(chan *dispatchJob, max(1, size/workers))
+		go work(dispatcher.queues[i])
+	}
+}
+
+func work(queue chan *dispatchJob) {
+	for job := range queue {
+		job.run()
+	}
+}
+
+/*
+runLane handles job after the jobs already in the lane of key,
+starting a goroutine for the lane if it is not running.
+
+The lane holds at most [DispatcherOptions.QueueSize] jobs,
+when it is full [DispatcherOptions.Policy] is applied.
+*/
+func (dispatcher *Dispatcher) runLane(key int64, job *dispatchJob) error {
+	size := dispatcher.queueSize()
+
+	for {
+		dispatcher.mu.Lock()
+
+		if dispatcher.lanes == nil {
+			dispatcher.lanes = make(map[int64]*dispatchLane)
+		}
+
+		lane, running := dispatcher.lanes[key]
+		if !running {
+			dispatcher.lanes[key] = &dispatchLane{space: make(chan struct{})}
+			dispatcher.mu.Unlock()
+
+			go dispatcher.drainLane(key, job)
+			return nil
+		}
+
+		if len(lane.jobs) < size {
+			lane.jobs = append(lane.jobs, job)
+			dispatcher.mu.Unlock()
+			return nil
+		}
+
+		switch dispatcher.Options.Policy {
+		case QueueDropOldest:
+			oldest := lane.jobs[0]
+			lane.jobs = append(lane.jobs[1:], job)
+			dispatcher.mu.Unlock()
+
+			oldest.drop(DropReasonQueueFull)
+			return nil
+
+		case QueueReject:
+			dispatcher.mu.Unlock()
+
+			job.drop(DropReasonQueueFull)
+			return ErrQueueFull
+		}
+
+		space := lane.space
+		dispatcher.mu.Unlock()
+
+		select {
+		case <-space:
+		case <-job.bot.stopContext.Done():
+			// never handled, so not acknowledged
+			job.bot.inFlight.Done()
+			return job.bot.stopContext.Err()
+		case <-dispatcher.stop:
+			job.bot.inFlight.Done()
+			return ErrDispatcherClosed
+		}
+	}
+}
+
+// drainLane runs job and then the jobs queued in the lane of key, until it is empty.
+func (dispatcher *Dispatcher) drainLane(key int64, job *dispatchJob) {
+	for {
+		job.run()
+
+		dispatcher.mu.Lock()
+
+		lane := dispatcher.lanes[key]
+		if len(lane.jobs) == 0 {
+			delete(dispatcher.lanes, key)
+			dispatcher.mu.Unlock()
+			return
+		}
+
+		job = lane.jobs[0]
+		lane.jobs = lane.jobs[1:]
+
+		close(lane.space)
+		lane.space = make(chan struct{})
+
+		dispatcher.mu.Unlock()
+	}
+}
+
+func (job *dispatchJob) done(handled bool) {
+	if job.ack != nil {
+		job.ack(handled)
+	}
+
+	job.bot.inFlight.Done()
+}
+
+func (job *dispatchJob) run() {
+	defer job.done(true)
+	job.bot.ProcessUpdate(job.update)
+}
+
+func (job *dispatchJob) drop(reason string) {
+	defer job.done(false)
+
+	job.bot.updateDropped(reason)
+	job.bot.Config.Logger.Warn("update dropped",
+		slog.Int("update_id", job.update.UpdateID),
+		slog.String("reason", reason),
+	)
+}
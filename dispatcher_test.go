@@ -0,0 +1,232 @@
+package aquagram_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+type droppedMetrics struct {
+	aquagram.Metrics
+
+	mu      sync.Mutex
+	dropped map[string]int
+}
+
+func (metrics *droppedMetrics) UpdateReceived(updateType aquagram.UpdateType) {}
+
+func (metrics *droppedMetrics) UpdateHandled(updateType aquagram.UpdateType, duration time.Duration, err error) {
+}
+
+func (metrics *droppedMetrics) UpdateDropped(reason string) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.dropped[reason]++
+}
+
+func newDispatcherBot(t *testing.T, policy aquagram.QueuePolicy) (*aquagram.Bot, *droppedMetrics, chan int64) {
+	metrics := &droppedMetrics{dropped: make(map[string]int)}
+
+	bot := aquagram.NewBot("token")
+	bot.Config.Metrics = metrics
+	bot.Config.Dispatcher = aquagram.NewDispatcher(&aquagram.DispatcherOptions{
+		Workers:   1,
+		QueueSize: 1,
+		Policy:    policy,
+	})
+
+	handled := make(chan int64, 10)
+	release := make(chan struct{})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		handled <- message.MessageID
+		<-release
+		return nil
+	})
+
+	t.Cleanup(func() {
+		close(release)
+		bot.Shutdown(context.Background())
+	})
+
+	return bot, metrics, handled
+}
+
+func messageUpdate(id int) *aquagram.Update {
+	return &aquagram.Update{
+		UpdateID: id,
+		Message:  &aquagram.Message{MessageID: int64(id), Chat: &aquagram.Chat{ID: 42}},
+	}
+}
+
+func TestDispatcherReject(t *testing.T) {
+	bot, metrics, handled := newDispatcherBot(t, aquagram.QueueReject)
+	dispatcher := bot.Config.Dispatcher
+
+	// taken by the worker
+	dispatcher.Dispatch(bot, messageUpdate(1))
+	<-handled
+
+	// waiting in the queue
+	if err := dispatcher.Dispatch(bot, messageUpdate(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dispatcher.Dispatch(bot, messageUpdate(3)); !errors.Is(err, aquagram.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	if metrics.dropped[aquagram.DropReasonQueueFull] != 1 {
+		t.Errorf("unexpected dropped updates %v", metrics.dropped)
+	}
+}
+
+func TestDispatcherDropOldest(t *testing.T) {
+	bot, metrics, handled := newDispatcherBot(t, aquagram.QueueDropOldest)
+	dispatcher := bot.Config.Dispatcher
+
+	dispatcher.Dispatch(bot, messageUpdate(1))
+	<-handled
+
+	for id := 2; id <= 4; id++ {
+		if err := dispatcher.Dispatch(bot, messageUpdate(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metrics.mu.Lock()
+	dropped := metrics.dropped[aquagram.DropReasonQueueFull]
+	metrics.mu.Unlock()
+
+	if dropped != 2 {
+		t.Errorf("expected 2 dropped updates, got %d", dropped)
+	}
+}
+
+func TestDispatcherOrderByChat(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		bot := aquagram.NewBot("token")
+		bot.Config.Dispatcher = aquagram.NewDispatcher(&aquagram.DispatcherOptions{
+			Workers: workers,
+			Order:   aquagram.OrderByChat,
+		})
+
+		var mu sync.Mutex
+		handled := make(map[int64][]int64)
+
+		bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+			time.Sleep(time.Duration(message.MessageID%3) * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled[message.Chat.ID] = append(handled[message.Chat.ID], message.MessageID)
+			return nil
+		})
+
+		for id := 1; id <= 60; id++ {
+			update := messageUpdate(id)
+			update.Message.Chat = &aquagram.Chat{ID: int64(id % 3)}
+
+			bot.Config.Dispatcher.Dispatch(bot, update)
+		}
+
+		if err := bot.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		for chatID, ids := range handled {
+			if len(ids) != 20 {
+				t.Errorf("workers %d: chat %d handled %d updates", workers, chatID, len(ids))
+			}
+
+			for i := 1; i < len(ids); i++ {
+				if ids[i] < ids[i-1] {
+					t.Errorf("workers %d: chat %d handled out of order %v", workers, chatID, ids)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestDispatcherLaneReject(t *testing.T) {
+	bot, metrics, handled := newDispatcherBot(t, aquagram.QueueReject)
+
+	dispatcher := aquagram.NewDispatcher(&aquagram.DispatcherOptions{
+		QueueSize: 1,
+		Policy:    aquagram.QueueReject,
+		Order:     aquagram.OrderByChat,
+	})
+
+	bot.Config.Dispatcher = dispatcher
+
+	dispatcher.Dispatch(bot, messageUpdate(1))
+	<-handled
+
+	// waiting in the lane of the chat
+	if err := dispatcher.Dispatch(bot, messageUpdate(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dispatcher.Dispatch(bot, messageUpdate(3)); !errors.Is(err, aquagram.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	// other chats have their own lane
+	other := messageUpdate(4)
+	other.Message.Chat = &aquagram.Chat{ID: 43}
+
+	if err := dispatcher.Dispatch(bot, other); err != nil {
+		t.Fatal(err)
+	}
+
+	if id := <-handled; id != 4 {
+		t.Errorf("unexpected handled update %d", id)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	if metrics.dropped[aquagram.DropReasonQueueFull] != 1 {
+		t.Errorf("unexpected dropped updates %v", metrics.dropped)
+	}
+}
+
+func TestDispatcherClose(t *testing.T) {
+	bot, _, handled := newDispatcherBot(t, aquagram.QueueBlock)
+	dispatcher := bot.Config.Dispatcher
+
+	dispatcher.Dispatch(bot, messageUpdate(1))
+	<-handled
+
+	if err := dispatcher.Dispatch(bot, messageUpdate(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the queue is full, it waits
+	blocked := make(chan error)
+
+	go func() {
+		blocked <- dispatcher.Dispatch(bot, messageUpdate(3))
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	dispatcher.Close()
+
+	if err := <-blocked; !errors.Is(err, aquagram.ErrDispatcherClosed) {
+		t.Errorf("expected ErrDispatcherClosed, got %v", err)
+	}
+
+	if err := dispatcher.Dispatch(bot, messageUpdate(4)); !errors.Is(err, aquagram.ErrDispatcherClosed) {
+		t.Errorf("expected ErrDispatcherClosed, got %v", err)
+	}
+
+	// closing again does nothing
+	dispatcher.Close()
+}
@@ -3,6 +3,8 @@ package aquagram
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 /*
@@ -19,11 +21,120 @@ var (
 	ErrEmptyToken        = fmt.Errorf("%w: empty bot token", ErrUserError)
 	ErrUnknownFileSource = fmt.Errorf("%w: unknown file source", ErrUserError)
 	ErrUnknownMarkup     = fmt.Errorf("%w: unknown reply markup", ErrUserError)
+	ErrBotAlreadyAdded   = fmt.Errorf("%w: bot already added", ErrUserError)
 
 	// telegram errors
-	ErrTelegramError = errors.New("telegram error")
-	ErrTgBadRequest  = fmt.Errorf("%w: bad request", ErrTelegramError)
-	ErrExpectedTrue  = fmt.Errorf("%w: the result is not true", ErrTelegramError)
+	ErrTelegramError     = errors.New("telegram error")
+	ErrTgBadRequest      = fmt.Errorf("%w: bad request", ErrTelegramError)
+	ErrTgUnauthorized    = fmt.Errorf("%w: unauthorized", ErrTelegramError)
+	ErrTgForbidden       = fmt.Errorf("%w: forbidden", ErrTelegramError)
+	ErrTgNotFound        = fmt.Errorf("%w: not found", ErrTelegramError)
+	ErrTgConflict        = fmt.Errorf("%w: conflict", ErrTelegramError)
+	ErrTgTooManyRequests = fmt.Errorf("%w: too many requests", ErrTelegramError)
+	ErrTgServerError     = fmt.Errorf("%w: server error", ErrTelegramError)
+	ErrExpectedTrue      = fmt.Errorf("%w: the result is not true", ErrTelegramError)
 
-	ErrUpdaterError = errors.New("updater error")
+	// telegram errors classified by description
+	ErrTgBlockedByUser      = fmt.Errorf("%w: bot was blocked by the user", ErrTgForbidden)
+	ErrTgChatNotFound       = fmt.Errorf("%w: chat not found", ErrTgBadRequest)
+	ErrTgMessageNotModified = fmt.Errorf("%w: message is not modified", ErrTgBadRequest)
+
+	// updater errors
+	ErrUpdaterError     = errors.New("updater error")
+	ErrQueueFull        = fmt.Errorf("%w: dispatcher queue is full", ErrUpdaterError)
+	ErrDuplicateUpdate  = fmt.Errorf("%w: duplicate update", ErrUpdaterError)
+	ErrDispatcherClosed = fmt.Errorf("%w: dispatcher is closed", ErrUpdaterError)
 )
+
+/*
+[ResponseParameters] - Describes why a request was unsuccessful.
+
+[ResponseParameters]: https://core.telegram.org/bots/api#responseparameters
+*/
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int64 `json:"retry_after,omitempty"`
+}
+
+/*
+[APIError] is returned when the Bot API answers a request with "ok": false.
+
+It can be inspected with [errors.As], or classified with [errors.Is]
+against sentinels like [ErrTgForbidden], [ErrTgBlockedByUser],
+[ErrTgChatNotFound], [ErrTgMessageNotModified] or [ErrTgTooManyRequests].
+
+For compatibility, every APIError also matches [ErrTgBadRequest],
+as every failed request did before; use ErrorCode to tell a 400 apart.
+*/
+type APIError struct {
+	ErrorCode   int
+	Description string
+
+	// Time to wait before the request can be repeated,
+	// only set when the flood control is exceeded.
+	RetryAfter time.Duration
+
+	// The group has been migrated to a supergroup with this identifier.
+	MigrateToChatID int64
+}
+
+func newAPIError(code int, description string, parameters *ResponseParameters) *APIError {
+	err := new(APIError)
+	err.ErrorCode = code
+	err.Description = description
+
+	if parameters != nil {
+		err.RetryAfter = time.Duration(parameters.RetryAfter) * time.Second
+		err.MigrateToChatID = parameters.MigrateToChatID
+	}
+
+	return err
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("%s: (%d): %s", err.kind(), err.ErrorCode, err.Description)
+}
+
+func (err *APIError) Unwrap() []error {
+	errs := []error{err.kind()}
+
+	// every failed request used to be a bad request
+	if err.ErrorCode != 400 {
+		errs = append(errs, ErrTgBadRequest)
+	}
+
+	description := strings.ToLower(err.Description)
+
+	switch {
+	case strings.Contains(description, "bot was blocked by the user"):
+		errs = append(errs, ErrTgBlockedByUser)
+	case strings.Contains(description, "chat not found"):
+		errs = append(errs, ErrTgChatNotFound)
+	case strings.Contains(description, "message is not modified"):
+		errs = append(errs, ErrTgMessageNotModified)
+	}
+
+	return errs
+}
+
+// kind returns the sentinel error matching the error code.
+func (err *APIError) kind() error {
+	switch {
+	case err.ErrorCode == 400:
+		return ErrTgBadRequest
+	case err.ErrorCode == 401:
+		return ErrTgUnauthorized
+	case err.ErrorCode == 403:
+		return ErrTgForbidden
+	case err.ErrorCode == 404:
+		return ErrTgNotFound
+	case err.ErrorCode == 409:
+		return ErrTgConflict
+	case err.ErrorCode == 429:
+		return ErrTgTooManyRequests
+	case err.ErrorCode >= 500:
+		return ErrTgServerError
+	default:
+		return ErrTelegramError
+	}
+}
@@ -0,0 +1,69 @@
+package aquagram_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+func TestAPIError(t *testing.T) {
+	bot := aquagram.NewBot("token")
+
+	data := []byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`)
+
+	_, err := aquagram.ParseRawResult[bool](bot, data)
+
+	var apiErr *aquagram.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+
+	if apiErr.ErrorCode != 429 || apiErr.RetryAfter != 5*time.Second {
+		t.Errorf("unexpected error fields: %+v", apiErr)
+	}
+
+	if !errors.Is(err, aquagram.ErrTgTooManyRequests) || !errors.Is(err, aquagram.ErrTelegramError) {
+		t.Errorf("error should wrap ErrTgTooManyRequests: %v", err)
+	}
+}
+
+func TestAPIErrorMigrateToChatID(t *testing.T) {
+	bot := aquagram.NewBot("token")
+
+	data := []byte(`{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001234}}`)
+
+	_, err := aquagram.ParseRawResult[bool](bot, data)
+
+	var apiErr *aquagram.APIError
+	if !errors.As(err, &apiErr) || apiErr.MigrateToChatID != -1001234 {
+		t.Errorf("expected migrate_to_chat_id to be parsed: %v", err)
+	}
+}
+
+func TestAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		code        int
+		description string
+		target      error
+	}{
+		{403, "Forbidden: bot was blocked by the user", aquagram.ErrTgBlockedByUser},
+		{403, "Forbidden: bot was blocked by the user", aquagram.ErrTgForbidden},
+		{400, "Bad Request: chat not found", aquagram.ErrTgChatNotFound},
+		{400, "Bad Request: chat not found", aquagram.ErrTgBadRequest},
+		{400, "Bad Request: message is not modified: specified new message content is the same", aquagram.ErrTgMessageNotModified},
+
+		// every failed request used to match ErrTgBadRequest
+		{403, "Forbidden: bot was blocked by the user", aquagram.ErrTgBadRequest},
+		{429, "Too Many Requests: retry after 5", aquagram.ErrTgBadRequest},
+	}
+
+	for _, test := range tests {
+		err := &aquagram.APIError{ErrorCode: test.code, Description: test.description}
+
+		if !errors.Is(err, test.target) {
+			t.Errorf("%q should match %v", test.description, test.target)
+		}
+	}
+}
@@ -150,3 +150,39 @@ func WhiteListFilter(ids *[]int64) FilterFunc {
 		return slices.Contains(*ids, from.ID), nil
 	}
 }
+
+// chatMemberUpdatedFilter matches the chat member updates for which match returns true.
+func chatMemberUpdatedFilter(match func(update *ChatMemberUpdated) bool) FilterFunc {
+	return func(bot *Bot, event Event) (bool, error) {
+		update, ok := event.(*ChatMemberUpdated)
+		if !ok {
+			return false, nil
+		}
+
+		return match(update), nil
+	}
+}
+
+func JoinFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).IsJoin)
+}
+
+func LeaveFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).IsLeave)
+}
+
+func PromotionFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).IsPromotion)
+}
+
+func BanFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).IsBan)
+}
+
+func BotAddedToGroupFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).BotAddedToGroup)
+}
+
+func BotRemovedFromGroupFilter() FilterFunc {
+	return chatMemberUpdatedFilter((*ChatMemberUpdated).BotRemovedFromGroup)
+}
@@ -0,0 +1,168 @@
+package aquagram
+
+import (
+	"context"
+	"time"
+)
+
+/*
+[InlineQuery] - This object represents an incoming inline query.
+When the user sends an empty query, your bot could return some default or trending results.
+
+[InlineQuery]: https://core.telegram.org/bots/api#inlinequery
+*/
+type InlineQuery struct {
+	Bot *Bot `json:"-"`
+
+	ID       string    `json:"id"`
+	From     *User     `json:"from"`
+	Query    string    `json:"query"`
+	Offset   string    `json:"offset"`
+	ChatType ChatType  `json:"chat_type,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+/*
+[Answer] is an alias for [AnswerInlineQuery]
+*/
+func (query *InlineQuery) Answer(results []InlineQueryResult, params *AnswerInlineQueryParams) error {
+	return query.Bot.AnswerInlineQuery(query.ID, results, params)
+}
+
+func (query *InlineQuery) GetMessage() *Message {
+	return nil
+}
+
+func (query *InlineQuery) GetFrom() *User {
+	return query.From
+}
+
+func (query *InlineQuery) GetChat() *Chat {
+	return nil
+}
+
+func (query *InlineQuery) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (query *InlineQuery) GetEntities() []*MessageEntity {
+	return nil
+}
+
+/*
+[ChosenInlineResult] - Represents a result of an inline query that was chosen by the user and sent to their chat partner.
+
+[ChosenInlineResult]: https://core.telegram.org/bots/api#choseninlineresult
+*/
+type ChosenInlineResult struct {
+	Bot *Bot `json:"-"`
+
+	ResultID        string    `json:"result_id"`
+	From            *User     `json:"from"`
+	Location        *Location `json:"location,omitempty"`
+	InlineMessageID string    `json:"inline_message_id,omitempty"`
+	Query           string    `json:"query"`
+}
+
+func (result *ChosenInlineResult) GetMessage() *Message {
+	return nil
+}
+
+func (result *ChosenInlineResult) GetFrom() *User {
+	return result.From
+}
+
+func (result *ChosenInlineResult) GetChat() *Chat {
+	return nil
+}
+
+func (result *ChosenInlineResult) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (result *ChosenInlineResult) GetEntities() []*MessageEntity {
+	return nil
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultsbutton
+type InlineQueryResultsButton struct {
+	Text           string      `json:"text"`
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"`
+}
+
+// https://core.telegram.org/bots/api#webappinfo
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+type AnswerInlineQueryParams struct {
+	InlineQueryID string                    `json:"inline_query_id"`
+	Results       []InlineQueryResult       `json:"results"`
+	CacheTime     time.Duration             `json:"-"`
+	CacheTimeRaw  int64                     `json:"cache_time,omitempty"`
+	IsPersonal    bool                      `json:"is_personal,omitempty"`
+	NextOffset    string                    `json:"next_offset,omitempty"` // 0-64 bytes, empty if there are no more results
+	Button        *InlineQueryResultsButton `json:"button,omitempty"`
+}
+
+/*
+[AnswerInlineQuery] wraps [AnswerInlineQueryWithContext] using the default bot context.
+*/
+func (bot *Bot) AnswerInlineQuery(inlineQueryID string, results []InlineQueryResult, params *AnswerInlineQueryParams) error {
+	return bot.AnswerInlineQueryWithContext(bot.stopContext, inlineQueryID, results, params)
+}
+
+/*
+[answerInlineQuery] - Use this method to send answers to an inline query. No more than 50 results per query are allowed.
+
+To paginate the results, set NextOffset to the offset that the client
+will send in [InlineQuery.Offset] when the user scrolls down.
+
+[answerInlineQuery]: https://core.telegram.org/bots/api#answerinlinequery
+*/
+func (bot *Bot) AnswerInlineQueryWithContext(ctx context.Context, inlineQueryID string, results []InlineQueryResult, params *AnswerInlineQueryParams) error {
+	if params == nil {
+		params = new(AnswerInlineQueryParams)
+	}
+
+	if results == nil {
+		results = []InlineQueryResult{}
+	}
+
+	params.InlineQueryID = inlineQueryID
+	params.Results = results
+	params.CacheTimeRaw = int64(params.CacheTime.Seconds())
+
+	data, err := bot.Raw(ctx, "answerInlineQuery", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+func (bot *Bot) OnInlineQuery(handler HandlerFunc[*InlineQuery], middlewares ...Middleware) *Handler {
+	queryHandler := new(Handler)
+	queryHandler.Middlewares = middlewares
+	queryHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnInlineQuery, queryHandler)
+}
+
+func (bot *Bot) OnChosenInlineResult(handler HandlerFunc[*ChosenInlineResult], middlewares ...Middleware) *Handler {
+	resultHandler := new(Handler)
+	resultHandler.Middlewares = middlewares
+	resultHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnChosenInlineResult, resultHandler)
+}
@@ -0,0 +1,594 @@
+package aquagram
+
+import (
+	"encoding/json"
+)
+
+/*
+[InlineQueryResult] - This object represents one result of an inline query.
+
+The type field is added when the result is marshaled.
+
+[InlineQueryResult]: https://core.telegram.org/bots/api#inlinequeryresult
+*/
+type InlineQueryResult interface {
+	ResultType() string
+}
+
+// marshalWithType marshals v adding the type field.
+func marshalWithType(resultType string, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	typeField, err := json.Marshal(resultType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []byte(`{"type":`)
+	result = append(result, typeField...)
+
+	if len(data) > 2 {
+		result = append(result, ',')
+	}
+
+	return append(result, data[1:]...), nil
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultarticle
+type InlineQueryResultArticle struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+func (result *InlineQueryResultArticle) ResultType() string {
+	return "article"
+}
+
+func (result *InlineQueryResultArticle) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultArticle
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultphoto
+type InlineQueryResultPhoto struct {
+	ID                    string                `json:"id"`
+	PhotoURL              string                `json:"photo_url"`
+	ThumbnailURL          string                `json:"thumbnail_url"`
+	PhotoWidth            int                   `json:"photo_width,omitempty"`
+	PhotoHeight           int                   `json:"photo_height,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultPhoto) ResultType() string {
+	return "photo"
+}
+
+func (result *InlineQueryResultPhoto) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultPhoto
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultgif
+type InlineQueryResultGif struct {
+	ID                    string                `json:"id"`
+	GifURL                string                `json:"gif_url"`
+	GifWidth              int                   `json:"gif_width,omitempty"`
+	GifHeight             int                   `json:"gif_height,omitempty"`
+	GifDuration           int                   `json:"gif_duration,omitempty"`
+	ThumbnailURL          string                `json:"thumbnail_url"`
+	ThumbnailMimeType     string                `json:"thumbnail_mime_type,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultGif) ResultType() string {
+	return "gif"
+}
+
+func (result *InlineQueryResultGif) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultGif
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultmpeg4gif
+type InlineQueryResultMpeg4Gif struct {
+	ID                    string                `json:"id"`
+	Mpeg4URL              string                `json:"mpeg4_url"`
+	Mpeg4Width            int                   `json:"mpeg4_width,omitempty"`
+	Mpeg4Height           int                   `json:"mpeg4_height,omitempty"`
+	Mpeg4Duration         int                   `json:"mpeg4_duration,omitempty"`
+	ThumbnailURL          string                `json:"thumbnail_url"`
+	ThumbnailMimeType     string                `json:"thumbnail_mime_type,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultMpeg4Gif) ResultType() string {
+	return "mpeg4_gif"
+}
+
+func (result *InlineQueryResultMpeg4Gif) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultMpeg4Gif
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultvideo
+type InlineQueryResultVideo struct {
+	ID                    string                `json:"id"`
+	VideoURL              string                `json:"video_url"`
+	MimeType              string                `json:"mime_type"`
+	ThumbnailURL          string                `json:"thumbnail_url"`
+	Title                 string                `json:"title"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	VideoWidth            int                   `json:"video_width,omitempty"`
+	VideoHeight           int                   `json:"video_height,omitempty"`
+	VideoDuration         int                   `json:"video_duration,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultVideo) ResultType() string {
+	return "video"
+}
+
+func (result *InlineQueryResultVideo) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultVideo
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultaudio
+type InlineQueryResultAudio struct {
+	ID                  string                `json:"id"`
+	AudioURL            string                `json:"audio_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	Performer           string                `json:"performer,omitempty"`
+	AudioDuration       int                   `json:"audio_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultAudio) ResultType() string {
+	return "audio"
+}
+
+func (result *InlineQueryResultAudio) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultAudio
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultvoice
+type InlineQueryResultVoice struct {
+	ID                  string                `json:"id"`
+	VoiceURL            string                `json:"voice_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	VoiceDuration       int                   `json:"voice_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultVoice) ResultType() string {
+	return "voice"
+}
+
+func (result *InlineQueryResultVoice) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultVoice
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultdocument
+type InlineQueryResultDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"` // "application/pdf" or "application/zip"
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+func (result *InlineQueryResultDocument) ResultType() string {
+	return "document"
+}
+
+func (result *InlineQueryResultDocument) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultDocument
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultlocation
+type InlineQueryResultLocation struct {
+	ID                   string                `json:"id"`
+	Latitude             float64               `json:"latitude"`
+	Longitude            float64               `json:"longitude"`
+	Title                string                `json:"title"`
+	HorizontalAccuracy   float64               `json:"horizontal_accuracy,omitempty"` // 0-1500 meters
+	LivePeriod           int                   `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL         string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth       int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight      int                   `json:"thumbnail_height,omitempty"`
+}
+
+func (result *InlineQueryResultLocation) ResultType() string {
+	return "location"
+}
+
+func (result *InlineQueryResultLocation) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultLocation
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultvenue
+type InlineQueryResultVenue struct {
+	ID                  string                `json:"id"`
+	Latitude            float64               `json:"latitude"`
+	Longitude           float64               `json:"longitude"`
+	Title               string                `json:"title"`
+	Address             string                `json:"address"`
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+func (result *InlineQueryResultVenue) ResultType() string {
+	return "venue"
+}
+
+func (result *InlineQueryResultVenue) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultVenue
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcontact
+type InlineQueryResultContact struct {
+	ID                  string                `json:"id"`
+	PhoneNumber         string                `json:"phone_number"`
+	FirstName           string                `json:"first_name"`
+	LastName            string                `json:"last_name,omitempty"`
+	Vcard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+func (result *InlineQueryResultContact) ResultType() string {
+	return "contact"
+}
+
+func (result *InlineQueryResultContact) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultContact
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultgame
+type InlineQueryResultGame struct {
+	ID            string                `json:"id"`
+	GameShortName string                `json:"game_short_name"`
+	ReplyMarkup   *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+func (result *InlineQueryResultGame) ResultType() string {
+	return "game"
+}
+
+func (result *InlineQueryResultGame) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultGame
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedphoto
+type InlineQueryResultCachedPhoto struct {
+	ID                    string                `json:"id"`
+	PhotoFileID           string                `json:"photo_file_id"`
+	Title                 string                `json:"title,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedPhoto) ResultType() string {
+	return "photo"
+}
+
+func (result *InlineQueryResultCachedPhoto) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedPhoto
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedgif
+type InlineQueryResultCachedGif struct {
+	ID                    string                `json:"id"`
+	GifFileID             string                `json:"gif_file_id"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedGif) ResultType() string {
+	return "gif"
+}
+
+func (result *InlineQueryResultCachedGif) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedGif
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedmpeg4gif
+type InlineQueryResultCachedMpeg4Gif struct {
+	ID                    string                `json:"id"`
+	Mpeg4FileID           string                `json:"mpeg4_file_id"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedMpeg4Gif) ResultType() string {
+	return "mpeg4_gif"
+}
+
+func (result *InlineQueryResultCachedMpeg4Gif) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedMpeg4Gif
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedsticker
+type InlineQueryResultCachedSticker struct {
+	ID                  string                `json:"id"`
+	StickerFileID       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedSticker) ResultType() string {
+	return "sticker"
+}
+
+func (result *InlineQueryResultCachedSticker) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedSticker
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcacheddocument
+type InlineQueryResultCachedDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileID      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedDocument) ResultType() string {
+	return "document"
+}
+
+func (result *InlineQueryResultCachedDocument) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedDocument
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedvideo
+type InlineQueryResultCachedVideo struct {
+	ID                    string                `json:"id"`
+	VideoFileID           string                `json:"video_file_id"`
+	Title                 string                `json:"title"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedVideo) ResultType() string {
+	return "video"
+}
+
+func (result *InlineQueryResultCachedVideo) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedVideo
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedvoice
+type InlineQueryResultCachedVoice struct {
+	ID                  string                `json:"id"`
+	VoiceFileID         string                `json:"voice_file_id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedVoice) ResultType() string {
+	return "voice"
+}
+
+func (result *InlineQueryResultCachedVoice) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedVoice
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+// https://core.telegram.org/bots/api#inlinequeryresultcachedaudio
+type InlineQueryResultCachedAudio struct {
+	ID                  string                `json:"id"`
+	AudioFileID         string                `json:"audio_file_id"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []*MessageEntity      `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (result *InlineQueryResultCachedAudio) ResultType() string {
+	return "audio"
+}
+
+func (result *InlineQueryResultCachedAudio) MarshalJSON() ([]byte, error) {
+	type plain InlineQueryResultCachedAudio
+	return marshalWithType(result.ResultType(), (*plain)(result))
+}
+
+/*
+[InputMessageContent] - This object represents the content of a message to be sent as a result of an inline query.
+
+[InputMessageContent]: https://core.telegram.org/bots/api#inputmessagecontent
+*/
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+// https://core.telegram.org/bots/api#inputtextmessagecontent
+type InputTextMessageContent struct {
+	MessageText        string              `json:"message_text"`
+	ParseMode          ParseMode           `json:"parse_mode,omitempty"`
+	Entities           []*MessageEntity    `json:"entities,omitempty"`
+	LinkPreviewOptions *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+}
+
+func (content *InputTextMessageContent) inputMessageContent() {}
+
+// https://core.telegram.org/bots/api#inputlocationmessagecontent
+type InputLocationMessageContent struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"` // 0-1500 meters
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+func (content *InputLocationMessageContent) inputMessageContent() {}
+
+// https://core.telegram.org/bots/api#inputvenuemessagecontent
+type InputVenueMessageContent struct {
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	Title           string  `json:"title"`
+	Address         string  `json:"address"`
+	FoursquareID    string  `json:"foursquare_id,omitempty"`
+	FoursquareType  string  `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string  `json:"google_place_id,omitempty"`
+	GooglePlaceType string  `json:"google_place_type,omitempty"`
+}
+
+func (content *InputVenueMessageContent) inputMessageContent() {}
+
+// https://core.telegram.org/bots/api#inputcontactmessagecontent
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	Vcard       string `json:"vcard,omitempty"`
+}
+
+func (content *InputContactMessageContent) inputMessageContent() {}
+
+// https://core.telegram.org/bots/api#inputinvoicemessagecontent
+type InputInvoiceMessageContent struct {
+	Title                     string         `json:"title"`
+	Description               string         `json:"description"`
+	Payload                   string         `json:"payload"`
+	ProviderToken             string         `json:"provider_token,omitempty"`
+	Currency                  string         `json:"currency"`
+	Prices                    []LabeledPrice `json:"prices"`
+	MaxTipAmount              int            `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int          `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              string         `json:"provider_data,omitempty"`
+	PhotoURL                  string         `json:"photo_url,omitempty"`
+	PhotoSize                 int            `json:"photo_size,omitempty"`
+	PhotoWidth                int            `json:"photo_width,omitempty"`
+	PhotoHeight               int            `json:"photo_height,omitempty"`
+	NeedName                  bool           `json:"need_name,omitempty"`
+	NeedPhoneNumber           bool           `json:"need_phone_number,omitempty"`
+	NeedEmail                 bool           `json:"need_email,omitempty"`
+	NeedShippingAddress       bool           `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool           `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool           `json:"send_email_to_provider,omitempty"`
+	IsFlexible                bool           `json:"is_flexible,omitempty"`
+}
+
+func (content *InputInvoiceMessageContent) inputMessageContent() {}
+
+/*
+[LabeledPrice] - This object represents a portion of the price for goods or services.
+
+[LabeledPrice]: https://core.telegram.org/bots/api#labeledprice
+*/
+type LabeledPrice struct {
+	Label string `json:"label"`
+
+	// Price in the smallest units of the currency, e.g. 145 for US$ 1.45
+	Amount int `json:"amount"`
+}
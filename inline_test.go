@@ -0,0 +1,75 @@
+package aquagram_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestAnswerInlineQuery(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	h.Bot.OnInlineQuery(func(bot *aquagram.Bot, query *aquagram.InlineQuery) error {
+		offset, _ := strconv.Atoi(query.Offset)
+
+		results := []aquagram.InlineQueryResult{
+			&aquagram.InlineQueryResultArticle{
+				ID:    strconv.Itoa(offset),
+				Title: query.Query,
+				InputMessageContent: &aquagram.InputTextMessageContent{
+					MessageText: query.Query,
+				},
+			},
+			&aquagram.InlineQueryResultCachedSticker{
+				ID:            "sticker",
+				StickerFileID: "file",
+			},
+		}
+
+		return query.Answer(results, &aquagram.AnswerInlineQueryParams{
+			NextOffset: strconv.Itoa(offset + 1),
+		})
+	})
+
+	user := h.User(42)
+
+	res := h.Dispatch(&aquagram.Update{
+		InlineQuery: &aquagram.InlineQuery{ID: "1", From: user.User, Query: "hello", Offset: "1"},
+	})
+
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	calls := res.Calls("answerInlineQuery")
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 answer, got %d", len(calls))
+	}
+
+	if calls[0].String("inline_query_id") != "1" || calls[0].String("next_offset") != "2" {
+		t.Errorf("unexpected answer %+v", calls[0].Params)
+	}
+
+	var results []map[string]any
+	if err := calls[0].Decode("results", &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("unexpected results %v", results)
+	}
+
+	if results[0]["type"] != "article" || results[0]["id"] != "1" || results[0]["title"] != "hello" {
+		t.Errorf("unexpected article %v", results[0])
+	}
+
+	if content, _ := results[0]["input_message_content"].(map[string]any); content["message_text"] != "hello" {
+		t.Errorf("unexpected message content %v", results[0]["input_message_content"])
+	}
+
+	if results[1]["type"] != "sticker" || results[1]["sticker_file_id"] != "file" {
+		t.Errorf("unexpected sticker %v", results[1])
+	}
+}
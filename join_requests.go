@@ -0,0 +1,279 @@
+package aquagram
+
+import (
+	"context"
+	"strconv"
+)
+
+/*
+[ChatJoinRequest] - Represents a join request sent to a chat.
+
+[ChatJoinRequest]: https://core.telegram.org/bots/api#chatjoinrequest
+*/
+type ChatJoinRequest struct {
+	Bot *Bot `json:"-"`
+
+	Chat *Chat `json:"chat"`
+	From *User `json:"from"`
+
+	// Private chat with the user, the bot can send messages to it for 5 minutes or until the request is processed
+	UserChatID int64 `json:"user_chat_id"`
+
+	Date       int64           `json:"date"`
+	Bio        string          `json:"bio,omitempty"`
+	InviteLink *ChatInviteLink `json:"invite_link,omitempty"`
+}
+
+/*
+[Approve] is an alias for [ApproveChatJoinRequest]
+*/
+func (request *ChatJoinRequest) Approve() error {
+	return request.Bot.ApproveChatJoinRequest(ChatID(request.Chat.ID), request.From.ID)
+}
+
+/*
+[Decline] is an alias for [DeclineChatJoinRequest]
+*/
+func (request *ChatJoinRequest) Decline() error {
+	return request.Bot.DeclineChatJoinRequest(ChatID(request.Chat.ID), request.From.ID)
+}
+
+func (request *ChatJoinRequest) GetMessage() *Message {
+	return nil
+}
+
+func (request *ChatJoinRequest) GetFrom() *User {
+	return request.From
+}
+
+func (request *ChatJoinRequest) GetChat() *Chat {
+	return request.Chat
+}
+
+func (request *ChatJoinRequest) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (request *ChatJoinRequest) GetEntities() []*MessageEntity {
+	return nil
+}
+
+/*
+[OnChatJoinRequest] registers handler for the join requests sent to the chats
+where the bot is an administrator with the can_invite_users right.
+*/
+func (bot *Bot) OnChatJoinRequest(handler HandlerFunc[*ChatJoinRequest], middlewares ...Middleware) *Handler {
+	requestHandler := new(Handler)
+	requestHandler.Middlewares = middlewares
+	requestHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnChatJoinRequest, requestHandler)
+}
+
+/*
+[ApproveChatJoinRequest] wraps [ApproveChatJoinRequestWithContext] using the default bot context.
+*/
+func (bot *Bot) ApproveChatJoinRequest(chatID string, userID int64) error {
+	return bot.ApproveChatJoinRequestWithContext(bot.stopContext, chatID, userID)
+}
+
+/*
+[approveChatJoinRequest] - Use this method to approve a chat join request.
+
+[approveChatJoinRequest]: https://core.telegram.org/bots/api#approvechatjoinrequest
+*/
+func (bot *Bot) ApproveChatJoinRequestWithContext(ctx context.Context, chatID string, userID int64) error {
+	params := map[string]string{
+		"chat_id": ParseChatID(chatID),
+		"user_id": strconv.FormatInt(userID, 10),
+	}
+
+	data, err := bot.Raw(ctx, "approveChatJoinRequest", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+/*
+[DeclineChatJoinRequest] wraps [DeclineChatJoinRequestWithContext] using the default bot context.
+*/
+func (bot *Bot) DeclineChatJoinRequest(chatID string, userID int64) error {
+	return bot.DeclineChatJoinRequestWithContext(bot.stopContext, chatID, userID)
+}
+
+/*
+[declineChatJoinRequest] - Use this method to decline a chat join request.
+
+[declineChatJoinRequest]: https://core.telegram.org/bots/api#declinechatjoinrequest
+*/
+func (bot *Bot) DeclineChatJoinRequestWithContext(ctx context.Context, chatID string, userID int64) error {
+	params := map[string]string{
+		"chat_id": ParseChatID(chatID),
+		"user_id": strconv.FormatInt(userID, 10),
+	}
+
+	data, err := bot.Raw(ctx, "declineChatJoinRequest", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+// ChatInviteLinkParams are the parameters shared by [CreateChatInviteLink] and [EditChatInviteLink].
+type ChatInviteLinkParams struct {
+	Name       string `json:"name,omitempty"` // 0-32 characters
+	ExpireDate int64  `json:"expire_date,omitempty"`
+
+	// Maximum number of users that can be members of the chat simultaneously
+	// after joining the chat via the link, 1-99999
+	MemberLimit int `json:"member_limit,omitempty"`
+
+	// Users joining the chat via the link need to be approved by chat administrators,
+	// see [Bot.OnChatJoinRequest]. MemberLimit can't be specified with it.
+	CreatesJoinRequest bool `json:"creates_join_request,omitempty"`
+}
+
+type CreateChatInviteLinkParams struct {
+	ChatInviteLinkParams
+	ChatID string `json:"chat_id"`
+}
+
+/*
+[CreateChatInviteLink] wraps [CreateChatInviteLinkWithContext] using the default bot context.
+*/
+func (bot *Bot) CreateChatInviteLink(chatID string, params *CreateChatInviteLinkParams) (*ChatInviteLink, error) {
+	return bot.CreateChatInviteLinkWithContext(bot.stopContext, chatID, params)
+}
+
+/*
+[createChatInviteLink] - Use this method to create an additional invite link for a chat.
+
+The bot must be an administrator in the chat with the appropriate administrator rights.
+
+[createChatInviteLink]: https://core.telegram.org/bots/api#createchatinvitelink
+*/
+func (bot *Bot) CreateChatInviteLinkWithContext(ctx context.Context, chatID string, params *CreateChatInviteLinkParams) (*ChatInviteLink, error) {
+	if params == nil {
+		params = new(CreateChatInviteLinkParams)
+	}
+
+	params.ChatID = ParseChatID(chatID)
+
+	data, err := bot.Raw(ctx, "createChatInviteLink", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*ChatInviteLink](bot, data)
+}
+
+type EditChatInviteLinkParams struct {
+	ChatInviteLinkParams
+	ChatID     string `json:"chat_id"`
+	InviteLink string `json:"invite_link"`
+}
+
+/*
+[EditChatInviteLink] wraps [EditChatInviteLinkWithContext] using the default bot context.
+*/
+func (bot *Bot) EditChatInviteLink(chatID string, inviteLink string, params *EditChatInviteLinkParams) (*ChatInviteLink, error) {
+	return bot.EditChatInviteLinkWithContext(bot.stopContext, chatID, inviteLink, params)
+}
+
+/*
+[editChatInviteLink] - Use this method to edit a non-primary invite link created by the bot.
+
+[editChatInviteLink]: https://core.telegram.org/bots/api#editchatinvitelink
+*/
+func (bot *Bot) EditChatInviteLinkWithContext(ctx context.Context, chatID string, inviteLink string, params *EditChatInviteLinkParams) (*ChatInviteLink, error) {
+	if params == nil {
+		params = new(EditChatInviteLinkParams)
+	}
+
+	params.ChatID = ParseChatID(chatID)
+	params.InviteLink = inviteLink
+
+	data, err := bot.Raw(ctx, "editChatInviteLink", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*ChatInviteLink](bot, data)
+}
+
+/*
+[RevokeChatInviteLink] wraps [RevokeChatInviteLinkWithContext] using the default bot context.
+*/
+func (bot *Bot) RevokeChatInviteLink(chatID string, inviteLink string) (*ChatInviteLink, error) {
+	return bot.RevokeChatInviteLinkWithContext(bot.stopContext, chatID, inviteLink)
+}
+
+/*
+[revokeChatInviteLink] - Use this method to revoke an invite link created by the bot.
+
+If the primary link is revoked, a new link is automatically generated.
+
+[revokeChatInviteLink]: https://core.telegram.org/bots/api#revokechatinvitelink
+*/
+func (bot *Bot) RevokeChatInviteLinkWithContext(ctx context.Context, chatID string, inviteLink string) (*ChatInviteLink, error) {
+	params := map[string]string{
+		"chat_id":     ParseChatID(chatID),
+		"invite_link": inviteLink,
+	}
+
+	data, err := bot.Raw(ctx, "revokeChatInviteLink", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*ChatInviteLink](bot, data)
+}
+
+/*
+[ExportChatInviteLink] wraps [ExportChatInviteLinkWithContext] using the default bot context.
+*/
+func (bot *Bot) ExportChatInviteLink(chatID string) (string, error) {
+	return bot.ExportChatInviteLinkWithContext(bot.stopContext, chatID)
+}
+
+/*
+[exportChatInviteLink] - Use this method to generate a new primary invite link for a chat,
+any previously generated primary link is revoked.
+
+On success, the new invite link is returned.
+
+[exportChatInviteLink]: https://core.telegram.org/bots/api#exportchatinvitelink
+*/
+func (bot *Bot) ExportChatInviteLinkWithContext(ctx context.Context, chatID string) (string, error) {
+	params := map[string]string{
+		"chat_id": ParseChatID(chatID),
+	}
+
+	data, err := bot.Raw(ctx, "exportChatInviteLink", params)
+	if err != nil {
+		return EmptyString, err
+	}
+
+	return ParseRawResult[string](bot, data)
+}
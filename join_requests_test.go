@@ -0,0 +1,88 @@
+package aquagram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestChatJoinRequest(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	group := h.Group(-100, "group")
+
+	link, err := h.Bot.CreateChatInviteLink(aquagram.ChatID(group.ID), &aquagram.CreateChatInviteLinkParams{
+		ChatInviteLinkParams: aquagram.ChatInviteLinkParams{Name: "gate", CreatesJoinRequest: true},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !link.CreatesJoinRequest || link.Name != "gate" || link.InviteLink == aquagram.EmptyString {
+		t.Errorf("unexpected invite link %+v", link)
+	}
+
+	h.Bot.OnChatJoinRequest(func(bot *aquagram.Bot, request *aquagram.ChatJoinRequest) error {
+		if strings.Contains(request.Bio, "spam") {
+			return request.Decline()
+		}
+
+		return request.Approve()
+	})
+
+	requests := []*aquagram.ChatJoinRequest{
+		{Chat: group, From: h.User(1).User, UserChatID: 1, Bio: "hello", InviteLink: link},
+		{Chat: group, From: h.User(2).User, UserChatID: 2, Bio: "cheap spam", InviteLink: link},
+	}
+
+	for _, request := range requests {
+		if res := h.Dispatch(&aquagram.Update{ChatJoinRequest: request}); res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+
+	if member := h.Server.ChatMember(group.ID, 1); !member.IsMember() {
+		t.Errorf("the request of user 1 was not approved, status %s", member.Status)
+	}
+
+	if member := h.Server.ChatMember(group.ID, 2); member != nil {
+		t.Errorf("the request of user 2 was not declined, status %s", member.Status)
+	}
+
+	if request := h.Server.LastRequest("declineChatJoinRequest"); request.Int64("user_id") != 2 {
+		t.Errorf("unexpected decline %+v", request.Params)
+	}
+
+	edited, err := h.Bot.EditChatInviteLink(aquagram.ChatID(group.ID), link.InviteLink, &aquagram.EditChatInviteLinkParams{
+		ChatInviteLinkParams: aquagram.ChatInviteLinkParams{Name: "open", MemberLimit: 10},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if edited.CreatesJoinRequest || edited.MemberLimit != 10 || edited.InviteLink != link.InviteLink {
+		t.Errorf("unexpected edited link %+v", edited)
+	}
+
+	revoked, err := h.Bot.RevokeChatInviteLink(aquagram.ChatID(group.ID), link.InviteLink)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !revoked.IsRevoked {
+		t.Errorf("the link was not revoked")
+	}
+
+	primary, err := h.Bot.ExportChatInviteLink(aquagram.ChatID(group.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if primary == aquagram.EmptyString || primary == link.InviteLink {
+		t.Errorf("unexpected primary link %q", primary)
+	}
+}
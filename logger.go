@@ -0,0 +1,57 @@
+package aquagram
+
+import (
+	"log"
+	"log/slog"
+)
+
+/*
+[LoggerFromStd] returns a [slog.Logger] that writes
+its records as text lines through logger.
+
+It keeps the output of code configured with a [log.Logger]:
+
+	bot.Config.Logger = aquagram.LoggerFromStd(log.New(os.Stderr, "[bot]: ", log.LstdFlags))
+*/
+func LoggerFromStd(logger *log.Logger) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			// the time is already written by logger
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}
+
+	return slog.New(slog.NewTextHandler(stdLogWriter{logger}, options))
+}
+
+type stdLogWriter struct {
+	logger *log.Logger
+}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	if err := w.logger.Output(2, string(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// eventAttrs returns the log attributes identifying the chat and the user of event.
+func eventAttrs(event Event) []any {
+	attrs := make([]any, 0, 2)
+
+	if chat := event.GetChat(); chat != nil {
+		attrs = append(attrs, slog.Int64("chat_id", chat.ID))
+	}
+
+	if from := event.GetFrom(); from != nil {
+		attrs = append(attrs, slog.Int64("user_id", from.ID))
+	}
+
+	return attrs
+}
@@ -0,0 +1,27 @@
+package aquagram_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+)
+
+func TestLoggerFromStd(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	logger := aquagram.LoggerFromStd(log.New(buf, "[aquagram]: ", 0))
+	logger.Info("update handled", "update_id", 1)
+
+	if line := buf.String(); line != "[aquagram]: level=INFO msg=\"update handled\" update_id=1\n" {
+		t.Errorf("unexpected output %q", line)
+	}
+
+	logger.Debug("hidden")
+
+	if strings.Contains(buf.String(), "hidden") {
+		t.Error("debug records should be discarded")
+	}
+}
@@ -0,0 +1,301 @@
+package aquagram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+[BotManager] serves the webhooks of many bots from one HTTP server,
+routing the updates by the path "/bot/<id>", where id is the user id of the bot.
+
+The bots share the same [Dispatcher] and [Limiter]:
+
+	manager := aquagram.NewBotManager()
+	manager.URL = "https://example.com"
+
+	manager.AddBot(aquagram.NewBot(token))
+	manager.ListenAndServe(":8080")
+
+Bots can be added and removed while the manager is serving.
+*/
+type BotManager struct {
+	// Public URL of the server, e.g. "https://example.com"
+	//
+	// When set, the webhook of a bot is registered with SetWebhook when it is added
+	// and deleted when it is removed.
+	URL string
+
+	// Dispatcher used by all the bots, by default is a new [Dispatcher]
+	// with [DefaultManagerWorkers] workers.
+	//
+	// It is closed by [BotManager.Shutdown].
+	Dispatcher *Dispatcher
+
+	// Limiter used by all the bots, by default is [NewRateLimiter].
+	//
+	// A [RateLimiter] limits every bot on its own, see [RateLimiter.ForBot].
+	Limiter Limiter
+
+	// Options used for the webhook of every bot, Addr, URL and Path are ignored.
+	//
+	// When SecretToken is empty, a random one is used for each bot.
+	WebhookOptions *WebhookOptions
+
+	mu     sync.RWMutex
+	bots   map[int64]*WebhookUpdater
+	adding map[int64]bool
+	server *http.Server
+}
+
+// DefaultManagerWorkers is the number of workers of the default dispatcher of a [BotManager].
+const DefaultManagerWorkers = 64
+
+func NewBotManager() *BotManager {
+	manager := new(BotManager)
+	manager.Dispatcher = NewDispatcher(&DispatcherOptions{Workers: DefaultManagerWorkers})
+	manager.Limiter = NewRateLimiter()
+	manager.bots = make(map[int64]*WebhookUpdater)
+	manager.adding = make(map[int64]bool)
+
+	return manager
+}
+
+/*
+[AddBot] starts bot with the shared dispatcher and limiter,
+registering its webhook if [BotManager.URL] is set.
+
+It returns [ErrBotAlreadyAdded] if a bot with the same id was added,
+the config of bot is only changed and [Config.OnStartFunc] is only called
+once it is added.
+*/
+func (manager *BotManager) AddBot(bot *Bot) error {
+	if err := bot.connect(); err != nil {
+		return err
+	}
+
+	id := bot.Me.ID
+
+	manager.mu.Lock()
+
+	if _, ok := manager.bots[id]; ok || manager.adding[id] {
+		manager.mu.Unlock()
+		return fmt.Errorf("%w: %d", ErrBotAlreadyAdded, id)
+	}
+
+	// reserved while the webhook is set
+	manager.adding[id] = true
+	manager.mu.Unlock()
+
+	defer func() {
+		manager.mu.Lock()
+		delete(manager.adding, id)
+		manager.mu.Unlock()
+	}()
+
+	options := new(WebhookOptions)
+	if manager.WebhookOptions != nil {
+		*options = *manager.WebhookOptions
+	}
+
+	options.Path = manager.path(id)
+
+	if options.SecretToken == EmptyString {
+		secretToken, err := randomSecretToken()
+		if err != nil {
+			return err
+		}
+
+		options.SecretToken = secretToken
+	}
+
+	updater := NewWebhookUpdater(bot)
+	updater.Options = options
+
+	if manager.URL != EmptyString {
+		url := strings.TrimSuffix(manager.URL, "/") + options.Path
+
+		if err := updater.setWebhook(bot.stopContext, url); err != nil {
+			return err
+		}
+	}
+
+	manager.mu.Lock()
+
+	bot.Config.Dispatcher = manager.Dispatcher
+	bot.Config.Limiter = manager.Limiter
+
+	// Telegram limits every bot on its own
+	if limiter, ok := manager.Limiter.(*RateLimiter); ok {
+		bot.Config.Limiter = limiter.ForBot(id)
+	}
+
+	manager.bots[id] = updater
+	manager.mu.Unlock()
+
+	bot.started()
+
+	return nil
+}
+
+/*
+[RemoveBot] stops routing the updates of the bot with the given id,
+deletes its webhook if [BotManager.URL] is set and shuts the bot down.
+*/
+func (manager *BotManager) RemoveBot(ctx context.Context, id int64) error {
+	manager.mu.Lock()
+
+	updater, ok := manager.bots[id]
+	delete(manager.bots, id)
+
+	manager.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return manager.stopBot(ctx, updater.Bot)
+}
+
+func (manager *BotManager) stopBot(ctx context.Context, bot *Bot) error {
+	var errs []error
+
+	if manager.URL != EmptyString {
+		if err := bot.DeleteWebhookWithContext(ctx, false); err != nil {
+			errs = append(errs, fmt.Errorf("%w: deleting webhook: %w", ErrUpdaterError, err))
+		}
+	}
+
+	errs = append(errs, bot.Shutdown(ctx))
+
+	return errors.Join(errs...)
+}
+
+// Bot returns the bot with the given id, or nil.
+func (manager *BotManager) Bot(id int64) *Bot {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	if updater, ok := manager.bots[id]; ok {
+		return updater.Bot
+	}
+
+	return nil
+}
+
+// Bots returns the bots of the manager, in any order.
+func (manager *BotManager) Bots() []*Bot {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	bots := make([]*Bot, 0, len(manager.bots))
+	for _, updater := range manager.bots {
+		bots = append(bots, updater.Bot)
+	}
+
+	return bots
+}
+
+// ServeHTTP passes the webhook requests to the bot of the path, it can be mounted in another server.
+func (manager *BotManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rawID, ok := strings.CutPrefix(r.URL.Path, "/bot/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	manager.mu.RLock()
+	updater, ok := manager.bots[id]
+	manager.mu.RUnlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	updater.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the webhooks on addr until [BotManager.Shutdown] is called.
+func (manager *BotManager) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: manager,
+	}
+
+	manager.mu.Lock()
+	manager.server = server
+	manager.mu.Unlock()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+/*
+[Shutdown] stops the server and then shuts down all the bots,
+deleting their webhooks if [BotManager.URL] is set.
+
+Finally the [BotManager.Dispatcher] is closed, stopping its workers.
+*/
+func (manager *BotManager) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	manager.mu.Lock()
+
+	server := manager.server
+	bots := manager.bots
+
+	manager.server = nil
+	manager.bots = make(map[int64]*WebhookUpdater)
+
+	manager.mu.Unlock()
+
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, updater := range bots {
+		if err := manager.stopBot(ctx, updater.Bot); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if manager.Dispatcher != nil {
+		manager.Dispatcher.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (manager *BotManager) path(id int64) string {
+	return "/bot/" + strconv.FormatInt(id, 10)
+}
+
+func randomSecretToken() (string, error) {
+	data := make([]byte, 32)
+
+	if _, err := rand.Read(data); err != nil {
+		return EmptyString, err
+	}
+
+	return hex.EncodeToString(data), nil
+}
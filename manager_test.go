@@ -0,0 +1,137 @@
+package aquagram_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestBotManager(t *testing.T) {
+	manager := aquagram.NewBotManager()
+
+	if manager.Dispatcher.Options.Workers <= 0 {
+		t.Errorf("the bots do not share a worker pool, %d workers", manager.Dispatcher.Options.Workers)
+	}
+
+	server := httptest.NewServer(manager)
+	defer server.Close()
+
+	manager.URL = server.URL
+
+	handled := make(chan string, 2)
+	fakes := make([]*aquagramtest.Server, 2)
+
+	for i := range fakes {
+		fake := aquagramtest.NewServer()
+		defer fake.Close()
+
+		fake.Me.ID = int64(1000 + i)
+		fakes[i] = fake
+
+		bot := fake.NewBot()
+		bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+			handled <- aquagram.ChatID(bot.Me.ID) + ": " + message.Text
+			return nil
+		})
+
+		if err := manager.AddBot(bot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(manager.Bots()) != 2 || manager.Bot(1001) == nil {
+		t.Fatalf("unexpected bots %v", manager.Bots())
+	}
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+
+	for _, fake := range fakes {
+		if webhook := fake.Webhook(); webhook == nil || !strings.HasSuffix(webhook.URL, "/bot/"+aquagram.ChatID(fake.Me.ID)) {
+			t.Fatalf("unexpected webhook %+v", webhook)
+		}
+
+		if _, err := fake.InjectMessage(user, aquagramtest.PrivateChat(user), "hello"); err != nil {
+			t.Fatal(err)
+		}
+
+		if text := <-handled; text != aquagram.ChatID(fake.Me.ID)+": hello" {
+			t.Errorf("unexpected message %q", text)
+		}
+	}
+
+	if err := manager.RemoveBot(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	if fakes[0].Webhook() != nil {
+		t.Error("the webhook was not deleted")
+	}
+
+	res, err := http.Post(server.URL+"/bot/1000", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", res.StatusCode)
+	}
+
+	if err := manager.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if fakes[1].Webhook() != nil || len(manager.Bots()) != 0 {
+		t.Error("the bots were not removed")
+	}
+
+	if err := manager.Dispatcher.Dispatch(aquagram.NewBot("token"), messageUpdate(1)); !errors.Is(err, aquagram.ErrDispatcherClosed) {
+		t.Errorf("the dispatcher was not closed, got %v", err)
+	}
+}
+
+func TestBotManagerDuplicate(t *testing.T) {
+	fake := aquagramtest.NewServer()
+	defer fake.Close()
+
+	manager := aquagram.NewBotManager()
+	defer manager.Shutdown(context.Background())
+
+	var started int
+
+	first := fake.NewBot()
+	first.Config.OnStartFunc = func(bot *aquagram.Bot) {
+		started++
+	}
+
+	if err := manager.AddBot(first); err != nil {
+		t.Fatal(err)
+	}
+
+	second := fake.NewBot()
+	second.Config.OnStartFunc = first.Config.OnStartFunc
+	limiter := second.Config.Limiter
+
+	if err := manager.AddBot(second); !errors.Is(err, aquagram.ErrBotAlreadyAdded) {
+		t.Fatalf("expected ErrBotAlreadyAdded, got %v", err)
+	}
+
+	if second.Config.Dispatcher != nil || second.Config.Limiter != limiter {
+		t.Error("the config of the duplicate bot was changed")
+	}
+
+	if manager.Bot(fake.Me.ID) != first {
+		t.Error("the first bot was replaced")
+	}
+
+	if started != 1 {
+		t.Errorf("the start hook was called %d times", started)
+	}
+}
@@ -27,6 +27,10 @@ type ChatMember struct {
 	IsAnonymus  bool             `json:"is_anonymus,omitempty"`
 	CustomTitle string           `json:"custom_title,omitempty"`
 	UntilDate   int64            `json:"until_date,omitempty"`
+
+	// Only for restricted members, true if the user is a member of the chat.
+	// Not named IsMember as the field of the API, that name is taken by [ChatMember.IsMember].
+	IsInChat bool `json:"is_member,omitempty"`
 }
 
 func (member *ChatMember) IsOwner() bool {
@@ -53,6 +57,164 @@ func (member *ChatMember) IsKicked() bool {
 	return member.Status == ChatMemberStatusKicked
 }
 
+// IsPresent returns true if the user is in the chat, restricted or not, false if member is nil.
+func (member *ChatMember) IsPresent() bool {
+	if member == nil {
+		return false
+	}
+
+	switch member.Status {
+	case ChatMemberStatusCreator, ChatMemberStatusAdministrator, ChatMemberStatusMember:
+		return true
+	case ChatMemberStatusRestricted:
+		return member.IsInChat
+	}
+
+	return false
+}
+
+/*
+[ChatMemberUpdated] - This object represents changes in the status of a chat member.
+
+[ChatMemberUpdated]: https://core.telegram.org/bots/api#chatmemberupdated
+*/
+type ChatMemberUpdated struct {
+	Bot *Bot `json:"-"`
+
+	Chat *Chat `json:"chat"`
+
+	// The user that performed the action
+	From *User `json:"from"`
+
+	Date                    int64           `json:"date"`
+	Old                     *ChatMember     `json:"old_chat_member"`
+	New                     *ChatMember     `json:"new_chat_member"`
+	InviteLink              *ChatInviteLink `json:"invite_link,omitempty"`
+	ViaJoinRequest          bool            `json:"via_join_request,omitempty"`
+	ViaChatFolderInviteLink bool            `json:"via_chat_folder_invite_link,omitempty"`
+
+	// received as my_chat_member, about the bot itself
+	my bool
+}
+
+// IsJoin returns true if the user was not in the chat and now it is.
+func (update *ChatMemberUpdated) IsJoin() bool {
+	return !update.Old.IsPresent() && update.New.IsPresent()
+}
+
+// IsLeave returns true if the user was in the chat and now it is not, also when banned.
+func (update *ChatMemberUpdated) IsLeave() bool {
+	return update.Old.IsPresent() && !update.New.IsPresent()
+}
+
+// IsPromotion returns true if the user became an administrator.
+func (update *ChatMemberUpdated) IsPromotion() bool {
+	return !update.Old.IsAdministrator() && !update.Old.IsOwner() && update.New.IsAdministrator()
+}
+
+// IsDemotion returns true if the user is no longer an administrator.
+func (update *ChatMemberUpdated) IsDemotion() bool {
+	return update.Old.IsAdministrator() && !update.New.IsAdministrator()
+}
+
+// IsBan returns true if the user was banned from the chat.
+func (update *ChatMemberUpdated) IsBan() bool {
+	return !update.Old.IsKicked() && update.New.IsKicked()
+}
+
+// IsUnban returns true if the user was unbanned from the chat.
+func (update *ChatMemberUpdated) IsUnban() bool {
+	return update.Old.IsKicked() && !update.New.IsKicked()
+}
+
+/*
+IsBot returns true if the updated member is the bot itself, as in every my_chat_member update.
+
+For other updates the member is compared with [Bot.Me].
+*/
+func (update *ChatMemberUpdated) IsBot() bool {
+	if update.my {
+		return true
+	}
+
+	if update.Bot == nil || update.Bot.Me == nil || update.New == nil || update.New.User == nil {
+		return false
+	}
+
+	return update.New.User.ID == update.Bot.Me.ID
+}
+
+// BotAddedToGroup returns true if the bot joined a group or a supergroup.
+func (update *ChatMemberUpdated) BotAddedToGroup() bool {
+	return update.IsBot() && update.IsJoin() && (update.Chat.IsGroup() || update.Chat.IsSuperGroup())
+}
+
+// BotRemovedFromGroup returns true if the bot left or was removed from a group or a supergroup.
+func (update *ChatMemberUpdated) BotRemovedFromGroup() bool {
+	return update.IsBot() && update.IsLeave() && (update.Chat.IsGroup() || update.Chat.IsSuperGroup())
+}
+
+func (update *ChatMemberUpdated) GetMessage() *Message {
+	return nil
+}
+
+func (update *ChatMemberUpdated) GetFrom() *User {
+	return update.From
+}
+
+func (update *ChatMemberUpdated) GetChat() *Chat {
+	return update.Chat
+}
+
+func (update *ChatMemberUpdated) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (update *ChatMemberUpdated) GetEntities() []*MessageEntity {
+	return nil
+}
+
+/*
+[ChatInviteLink] - Represents an invite link for a chat.
+
+[ChatInviteLink]: https://core.telegram.org/bots/api#chatinvitelink
+*/
+type ChatInviteLink struct {
+	InviteLink              string `json:"invite_link"`
+	Creator                 *User  `json:"creator"`
+	CreatesJoinRequest      bool   `json:"creates_join_request"`
+	IsPrimary               bool   `json:"is_primary"`
+	IsRevoked               bool   `json:"is_revoked"`
+	Name                    string `json:"name,omitempty"`
+	ExpireDate              int64  `json:"expire_date,omitempty"`
+	MemberLimit             int    `json:"member_limit,omitempty"`
+	PendingJoinRequestCount int    `json:"pending_join_request_count,omitempty"`
+	SubscriptionPeriod      int    `json:"subscription_period,omitempty"`
+	SubscriptionPrice       int    `json:"subscription_price,omitempty"`
+}
+
+func (bot *Bot) OnMyChatMember(handler HandlerFunc[*ChatMemberUpdated], middlewares ...Middleware) *Handler {
+	memberHandler := new(Handler)
+	memberHandler.Middlewares = middlewares
+	memberHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnMyChatMember, memberHandler)
+}
+
+/*
+[OnChatMember] registers handler for the status changes of the chat members.
+
+The bot must be an administrator in the chat and chat_member
+must be specified in [Config.AllowedUpdates] to receive them.
+*/
+func (bot *Bot) OnChatMember(handler HandlerFunc[*ChatMemberUpdated], middlewares ...Middleware) *Handler {
+	memberHandler := new(Handler)
+	memberHandler.Middlewares = middlewares
+	memberHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnChatMember, memberHandler)
+}
+
 type ChatMemberAdministratorPermissions struct {
 	CanBeEdited         bool `json:"can_be_edited,omitempty"`
 	CanManageChat       bool `json:"can_manage_chat,omitempty"`
@@ -0,0 +1,125 @@
+package aquagram_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestChatMemberUpdated(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	group := h.Group(-100, "group")
+	user := h.User(42)
+
+	var removed []int64
+
+	h.Bot.OnChatMember(func(bot *aquagram.Bot, update *aquagram.ChatMemberUpdated) error {
+		_, err := bot.SendMessage(aquagram.ChatID(update.Chat.ID), "welcome "+update.New.User.FirstName, nil)
+		return err
+	}, aquagram.JoinMiddleware())
+
+	h.Bot.OnMyChatMember(func(bot *aquagram.Bot, update *aquagram.ChatMemberUpdated) error {
+		removed = append(removed, update.Chat.ID)
+		return nil
+	}, aquagram.BotRemovedFromGroupMiddleware())
+
+	var update aquagram.Update
+
+	err := json.Unmarshal([]byte(`{
+		"update_id": 1,
+		"chat_member": {
+			"chat": {"id": -100, "type": "supergroup"},
+			"from": {"id": 42, "first_name": "User 42"},
+			"date": 1,
+			"old_chat_member": {"status": "restricted", "user": {"id": 42, "first_name": "User 42"}, "is_member": false},
+			"new_chat_member": {"status": "restricted", "user": {"id": 42, "first_name": "User 42"}, "is_member": true}
+		}
+	}`), &update)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := h.Dispatch(&update)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if texts := res.Texts(); len(texts) != 1 || texts[0] != "welcome User 42" {
+		t.Errorf("unexpected welcome messages %v", texts)
+	}
+
+	left := &aquagram.ChatMemberUpdated{
+		Chat: group,
+		From: user.User,
+		Old:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusMember, User: user.User},
+		New:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusLeft, User: user.User},
+	}
+
+	if res := h.Dispatch(&aquagram.Update{ChatMember: left}); len(res.Texts()) != 0 {
+		t.Errorf("a leaving user was welcomed")
+	}
+
+	if !left.IsLeave() || left.IsJoin() || left.IsBan() {
+		t.Errorf("unexpected transition of %+v", left)
+	}
+
+	kicked := &aquagram.ChatMemberUpdated{
+		Chat: group,
+		From: user.User,
+		Old:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusAdministrator, User: h.Bot.Me},
+		New:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusKicked, User: h.Bot.Me},
+	}
+
+	if res := h.Dispatch(&aquagram.Update{MyChatMember: kicked}); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if len(removed) != 1 || removed[0] != group.ID {
+		t.Errorf("the removal of the bot was not handled, %v", removed)
+	}
+
+	if !kicked.IsBan() || !kicked.IsDemotion() || kicked.BotAddedToGroup() {
+		t.Errorf("unexpected transition of %+v", kicked)
+	}
+}
+
+func TestMyChatMemberWithoutMe(t *testing.T) {
+	bot := aquagram.NewBot("token")
+
+	var added []int64
+
+	bot.OnMyChatMember(func(bot *aquagram.Bot, update *aquagram.ChatMemberUpdated) error {
+		added = append(added, update.Chat.ID)
+		return nil
+	}, aquagram.BotAddedToGroupMiddleware())
+
+	// Bot.Me is not known before the bot is started
+	me := &aquagram.User{ID: 1, IsBot: true}
+
+	bot.ProcessUpdate(&aquagram.Update{
+		UpdateID: 1,
+		MyChatMember: &aquagram.ChatMemberUpdated{
+			Chat: &aquagram.Chat{ID: -100, Type: aquagram.ChatTypeSuperGroup},
+			Old:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusLeft, User: me},
+			New:  &aquagram.ChatMember{Status: aquagram.ChatMemberStatusMember, User: me},
+		},
+	})
+
+	if len(added) != 1 || added[0] != -100 {
+		t.Errorf("the bot being added was not handled, %v", added)
+	}
+}
+
+func TestChatMemberUpdatedWithoutMembers(t *testing.T) {
+	update := &aquagram.ChatMemberUpdated{
+		New: &aquagram.ChatMember{Status: aquagram.ChatMemberStatusMember},
+	}
+
+	if !update.IsJoin() || update.IsLeave() || update.IsBot() {
+		t.Errorf("unexpected transition of %+v", update)
+	}
+}
@@ -14,45 +14,49 @@ type MessageID struct {
 type Message struct {
 	Bot *Bot `json:"-"`
 
-	MessageID             int64               `json:"message_id"`
-	MessageThreadID       int64               `json:"message_thread_id,omitempty"`
-	From                  *User               `json:"from,omitempty"`
-	SenderChat            *Chat               `json:"sender_chat,omitempty"`
-	SenderBoostCount      int                 `json:"sender_boost_count,omitempty"`
-	SenderBusinessBot     *User               `json:"sender_business_bot,omitempty"`
-	Date                  int64               `json:"date"`
-	BusinessConnectionID  string              `json:"business_connection_id,omitempty"`
-	Chat                  *Chat               `json:"chat"`
-	ForwardOrigin         MessageOrigin       `json:"forward_origin,omitempty"`
-	IsTopicMessage        bool                `json:"is_topic_message,omitempty"`
-	IsAutomaticMessage    bool                `json:"is_automatic_forward,omitempty"`
-	ReplyToMessage        *Message            `json:"reply_to_message,omitempty"`
-	ExternalReply         *ExternalReply      `json:"external_reply,omitempty"`
-	Quote                 *TextQuote          `json:"quote,omitempty"`
-	ViaBot                *User               `json:"via_bot,omitempty"`
-	EditDate              int64               `json:"edit_date,omitempty"`
-	HasProtectedContent   bool                `json:"has_protected_content,omitempty"`
-	IsFromOffline         bool                `json:"is_from_offline,omitempty"`
-	MediaGroupID          string              `json:"media_group_id,omitempty"`
-	AuthorSignature       string              `json:"author_signature,omitempty"`
-	Text                  string              `json:"text"`
-	Entities              []*MessageEntity    `json:"entities,omitempty"`
-	LinkPreviewOptions    *LinkPreviewOptions `json:"link_preview_options,omitempty"`
-	EffectID              string              `json:"effect_id,omitempty"`
-	Animation             *Animation          `json:"animation,omitempty"`
-	Audio                 *Audio              `json:"audio,omitempty"`
-	Document              *Document           `json:"document,omitempty"`
-	PaidMedia             *PaidMediaInfo      `json:"paid_media_info,omitempty"`
-	Photo                 []PhotoSize         `json:"photo,omitempty"`
-	Sticker               *Sticker            `json:"sticker,omitempty"`
-	Story                 *Story              `json:"story,omitempty"`
-	Video                 *Video              `json:"video,omitempty"`
-	VideoNote             *VideoNote          `json:"video_note,omitempty"`
-	Voice                 *Voice              `json:"voice,omitempty"`
-	Caption               string              `json:"caption,omitempty"`
-	CaptionEntities       []*MessageEntity    `json:"caption_entities,omitempty"`
-	ShowCaptionAboveMedia bool                `json:"show_caption_above_media,omitempty"`
-	HasMediaSpoiler       bool                `json:"has_media_spoiler,omitempty"`
+	MessageID             int64                 `json:"message_id"`
+	MessageThreadID       int64                 `json:"message_thread_id,omitempty"`
+	From                  *User                 `json:"from,omitempty"`
+	SenderChat            *Chat                 `json:"sender_chat,omitempty"`
+	SenderBoostCount      int                   `json:"sender_boost_count,omitempty"`
+	SenderBusinessBot     *User                 `json:"sender_business_bot,omitempty"`
+	Date                  int64                 `json:"date"`
+	BusinessConnectionID  string                `json:"business_connection_id,omitempty"`
+	Chat                  *Chat                 `json:"chat"`
+	ForwardOrigin         MessageOrigin         `json:"forward_origin,omitempty"`
+	IsTopicMessage        bool                  `json:"is_topic_message,omitempty"`
+	IsAutomaticMessage    bool                  `json:"is_automatic_forward,omitempty"`
+	ReplyToMessage        *Message              `json:"reply_to_message,omitempty"`
+	ExternalReply         *ExternalReply        `json:"external_reply,omitempty"`
+	Quote                 *TextQuote            `json:"quote,omitempty"`
+	ViaBot                *User                 `json:"via_bot,omitempty"`
+	EditDate              int64                 `json:"edit_date,omitempty"`
+	HasProtectedContent   bool                  `json:"has_protected_content,omitempty"`
+	IsFromOffline         bool                  `json:"is_from_offline,omitempty"`
+	MediaGroupID          string                `json:"media_group_id,omitempty"`
+	AuthorSignature       string                `json:"author_signature,omitempty"`
+	Text                  string                `json:"text"`
+	Entities              []*MessageEntity      `json:"entities,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions   `json:"link_preview_options,omitempty"`
+	EffectID              string                `json:"effect_id,omitempty"`
+	Animation             *Animation            `json:"animation,omitempty"`
+	Audio                 *Audio                `json:"audio,omitempty"`
+	Document              *Document             `json:"document,omitempty"`
+	PaidMedia             *PaidMediaInfo        `json:"paid_media_info,omitempty"`
+	Photo                 []PhotoSize           `json:"photo,omitempty"`
+	Sticker               *Sticker              `json:"sticker,omitempty"`
+	Story                 *Story                `json:"story,omitempty"`
+	Video                 *Video                `json:"video,omitempty"`
+	VideoNote             *VideoNote            `json:"video_note,omitempty"`
+	Voice                 *Voice                `json:"voice,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	CaptionEntities       []*MessageEntity      `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	HasMediaSpoiler       bool                  `json:"has_media_spoiler,omitempty"`
+	Poll                  *Poll                 `json:"poll,omitempty"`
+	Invoice               *Invoice              `json:"invoice,omitempty"`
+	SuccessfulPayment     *SuccessfulPayment    `json:"successful_payment,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
 type MessageOrigin struct{}
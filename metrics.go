@@ -0,0 +1,308 @@
+package aquagram
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of an API request reported to [Metrics].
+const (
+	OutcomeOK       = "ok"
+	OutcomeAPIError = "api_error"
+	OutcomeError    = "error"
+)
+
+// Reasons of a dropped update reported to [Metrics].
+const (
+	DropReasonUnauthorized = "unauthorized"
+	DropReasonInvalid      = "invalid"
+	DropReasonDuplicate    = "duplicate"
+	DropReasonQueueFull    = "queue_full"
+)
+
+// Metrics receives measurements about the bot, see [PrometheusMetrics].
+type Metrics interface {
+	// APICall is called after every API request with its outcome,
+	// one of [OutcomeOK], [OutcomeAPIError] or [OutcomeError].
+	APICall(method string, outcome string, duration time.Duration)
+
+	// UpdateReceived is called for every update received by an updater.
+	UpdateReceived(updateType UpdateType)
+
+	// UpdateHandled is called after the handlers of an update type run.
+	UpdateHandled(updateType UpdateType, duration time.Duration, err error)
+
+	// UpdateDropped is called when an update is discarded before being handled.
+	UpdateDropped(reason string)
+}
+
+func outcome(err error) string {
+	if err == nil {
+		return OutcomeOK
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return OutcomeAPIError
+	}
+
+	return OutcomeError
+}
+
+func (bot *Bot) updateReceived(update *Update) {
+	if bot.Config.Metrics != nil {
+		bot.Config.Metrics.UpdateReceived(update.Type())
+	}
+}
+
+func (bot *Bot) updateDropped(reason string) {
+	if bot.Config.Metrics != nil {
+		bot.Config.Metrics.UpdateDropped(reason)
+	}
+}
+
+// DefaultBuckets are the upper bounds, in seconds, of the histograms of [PrometheusMetrics].
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+/*
+[PrometheusMetrics] implements [Metrics] and serves them
+with the Prometheus text-based exposition format:
+
+	metrics := aquagram.NewPrometheusMetrics()
+	bot.Config.Metrics = metrics
+
+	http.Handle("/metrics", metrics)
+*/
+type PrometheusMetrics struct {
+	// Prefix of the metric names, by default is "aquagram".
+	Namespace string
+
+	// Upper bounds of the histograms, by default are [DefaultBuckets].
+	Buckets []float64
+
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	metrics := new(PrometheusMetrics)
+	metrics.Namespace = "aquagram"
+	metrics.Buckets = DefaultBuckets
+
+	return metrics
+}
+
+func (metrics *PrometheusMetrics) APICall(method string, outcome string, duration time.Duration) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.counter("api_requests_total", "Number of API requests by method and outcome.", []string{"method", "outcome"}, method, outcome).value++
+	metrics.histogram("api_request_duration_seconds", "Latency of the API requests.", []string{"method"}, method).observe(duration)
+}
+
+func (metrics *PrometheusMetrics) UpdateReceived(updateType UpdateType) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.counter("updates_received_total", "Number of updates received by type.", []string{"update_type"}, string(updateType)).value++
+}
+
+func (metrics *PrometheusMetrics) UpdateHandled(updateType UpdateType, duration time.Duration, err error) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.histogram("handler_duration_seconds", "Time spent running the handlers of an update type.", []string{"update_type"}, string(updateType)).observe(duration)
+
+	handlerErrors := metrics.counter("handler_errors_total", "Number of errors returned by the handlers.", []string{"update_type"}, string(updateType))
+
+	if err != nil {
+		handlerErrors.value++
+	}
+}
+
+func (metrics *PrometheusMetrics) UpdateDropped(reason string) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.counter("updates_dropped_total", "Number of updates discarded before being handled.", []string{"reason"}, reason).value++
+}
+
+func (metrics *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w with the Prometheus text-based exposition format.
+func (metrics *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	builder := new(strings.Builder)
+
+	names := make([]string, 0, len(metrics.families))
+	for name := range metrics.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		metrics.families[name].write(builder)
+	}
+
+	n, err := io.WriteString(w, builder.String())
+	return int64(n), err
+}
+
+type metricFamily struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+	series map[string]*metricSeries
+}
+
+type metricSeries struct {
+	labelValues []string
+
+	// counter
+	value float64
+
+	// histogram
+	bounds  []float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+func (series *metricSeries) observe(duration time.Duration) {
+	seconds := duration.Seconds()
+
+	for i, bound := range series.bounds {
+		if seconds <= bound {
+			series.buckets[i]++
+		}
+	}
+
+	series.sum += seconds
+	series.count++
+}
+
+func (metrics *PrometheusMetrics) counter(name string, help string, labels []string, values ...string) *metricSeries {
+	return metrics.series(name, help, "counter", labels, values)
+}
+
+func (metrics *PrometheusMetrics) histogram(name string, help string, labels []string, values ...string) *metricSeries {
+	series := metrics.series(name, help, "histogram", labels, values)
+
+	if series.bounds == nil {
+		series.bounds = metrics.Buckets
+		if series.bounds == nil {
+			series.bounds = DefaultBuckets
+		}
+
+		series.buckets = make([]uint64, len(series.bounds))
+	}
+
+	return series
+}
+
+func (metrics *PrometheusMetrics) series(name string, help string, kind string, labels []string, values []string) *metricSeries {
+	if metrics.families == nil {
+		metrics.families = make(map[string]*metricFamily)
+	}
+
+	if metrics.Namespace != EmptyString {
+		name = metrics.Namespace + "_" + name
+	}
+
+	family, ok := metrics.families[name]
+	if !ok {
+		family = &metricFamily{
+			name:   name,
+			help:   help,
+			kind:   kind,
+			labels: labels,
+			series: make(map[string]*metricSeries),
+		}
+
+		metrics.families[name] = family
+	}
+
+	key := strings.Join(values, "\xff")
+
+	series, ok := family.series[key]
+	if !ok {
+		series = &metricSeries{labelValues: values}
+		family.series[key] = series
+	}
+
+	return series
+}
+
+func (family *metricFamily) write(builder *strings.Builder) {
+	fmt.Fprintf(builder, "# HELP %s %s\n", family.name, family.help)
+	fmt.Fprintf(builder, "# TYPE %s %s\n", family.name, family.kind)
+
+	keys := make([]string, 0, len(family.series))
+	for key := range family.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		series := family.series[key]
+		labels := formatLabels(family.labels, series.labelValues)
+
+		if family.kind == "counter" {
+			fmt.Fprintf(builder, "%s%s %s\n", family.name, labels, formatFloat(series.value))
+			continue
+		}
+
+		names := append([]string{}, family.labels...)
+		names = append(names, "le")
+
+		values := append([]string{}, series.labelValues...)
+		values = append(values, EmptyString)
+
+		for i, bound := range series.bounds {
+			values[len(values)-1] = formatFloat(bound)
+			fmt.Fprintf(builder, "%s_bucket%s %d\n", family.name, formatLabels(names, values), series.buckets[i])
+		}
+
+		values[len(values)-1] = "+Inf"
+		infLabels := formatLabels(names, values)
+
+		fmt.Fprintf(builder, "%s_bucket%s %d\n", family.name, infLabels, series.count)
+		fmt.Fprintf(builder, "%s_sum%s %s\n", family.name, labels, formatFloat(series.sum))
+		fmt.Fprintf(builder, "%s_count%s %d\n", family.name, labels, series.count)
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return EmptyString
+	}
+
+	pairs := make([]string, len(names))
+
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelValueReplacer.Replace(values[i]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
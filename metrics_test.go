@@ -0,0 +1,58 @@
+package aquagram_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	server.AddChat(&aquagram.Chat{ID: 42, Type: aquagram.ChatTypePrivate})
+
+	metrics := aquagram.NewPrometheusMetrics()
+
+	bot := server.NewBot()
+	bot.Config.OnErrorFunc = nil
+	bot.Config.Metrics = metrics
+
+	bot.SendMessage("42", "hello", nil)
+	bot.SendMessage("-100", "hello", nil)
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		return aquagram.ErrExpectedTrue
+	})
+
+	update := &aquagram.Update{
+		UpdateID: 1,
+		Message:  &aquagram.Message{Chat: &aquagram.Chat{ID: 42}, Text: "hi"},
+	}
+
+	bot.ProcessUpdate(update)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := recorder.Body.String()
+
+	expected := []string{
+		"# TYPE aquagram_api_requests_total counter\n",
+		`aquagram_api_requests_total{method="sendMessage",outcome="ok"} 1`,
+		`aquagram_api_requests_total{method="sendMessage",outcome="api_error"} 1`,
+		`aquagram_api_request_duration_seconds_count{method="sendMessage"} 2`,
+		`aquagram_api_request_duration_seconds_bucket{method="sendMessage",le="+Inf"} 2`,
+		`aquagram_handler_errors_total{update_type="message"} 1`,
+		`aquagram_handler_duration_seconds_count{update_type="message"} 1`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("missing %q in:\n%s", line, body)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package aquagram
 
 import (
 	"fmt"
+	"log/slog"
 	"regexp"
 )
 
@@ -14,10 +15,22 @@ func (bot *Bot) Use(middlewares ...Middleware) {
 	bot.Middlewares = append(bot.Middlewares, middlewares...)
 }
 
+func BanMiddleware() Middleware {
+	return BuildMiddleware(BanFilter())
+}
+
 func BlackListMiddleware(ids *[]int64) Middleware {
 	return BuildMiddleware(WhiteListFilter(ids))
 }
 
+func BotAddedToGroupMiddleware() Middleware {
+	return BuildMiddleware(BotAddedToGroupFilter())
+}
+
+func BotRemovedFromGroupMiddleware() Middleware {
+	return BuildMiddleware(BotRemovedFromGroupFilter())
+}
+
 func CallbackQueryMiddleware(data string, strict bool) Middleware {
 	return BuildMiddleware(CallbackQueryFilter(data, strict))
 }
@@ -30,29 +43,47 @@ func CommandMiddleware(command string, strict bool) Middleware {
 	return BuildMiddleware(CommandFilter(command))
 }
 
+func JoinMiddleware() Middleware {
+	return BuildMiddleware(JoinFilter())
+}
+
+func LeaveMiddleware() Middleware {
+	return BuildMiddleware(LeaveFilter())
+}
+
+func PromotionMiddleware() Middleware {
+	return BuildMiddleware(PromotionFilter())
+}
+
+/*
+[RecoverMiddleware] recovers from panics in the next handlers,
+the panic is returned as the error of the handler.
+
+The panic is reported to errorFunc, or logged if it is nil.
+*/
 func RecoverMiddleware(errorFunc ErrorFunc) Middleware {
 	return func(next MiddlewareFunc) MiddlewareFunc {
-		return func(bot *Bot, event Event) error {
+		return func(bot *Bot, event Event) (err error) {
 			defer func() {
-				// Currently, err is always nil and I dont know why.
-				// However, it stops the the panicking sequence, so ¯⁠\⁠_⁠(⁠ツ⁠)⁠_⁠/⁠¯
-				err := recover()
-
-				if err != nil {
-					if errorFunc != nil {
-						errorFunc(bot, fmt.Errorf("%v", err))
-					} else {
-						bot.Config.Logger.Println("recovered from panic", err)
-					}
+				recovered := recover()
+				if recovered == nil {
 					return
 				}
 
-				switch v := err.(type) {
-				case error:
-					errorFunc(bot, v)
-				default:
-					errorFunc(bot, fmt.Errorf("%v", err))
+				panicErr, ok := recovered.(error)
+				if !ok {
+					panicErr = fmt.Errorf("%v", recovered)
 				}
+
+				err = fmt.Errorf("recovered from panic: %w", panicErr)
+
+				if errorFunc != nil {
+					errorFunc(bot, err)
+					return
+				}
+
+				attrs := append(eventAttrs(event), slog.Any("panic", recovered))
+				bot.Config.Logger.Error("recovered from panic", attrs...)
 			}()
 
 			return next(bot, event)
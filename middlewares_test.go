@@ -0,0 +1,33 @@
+package aquagram_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	boom := errors.New("boom")
+
+	var reported error
+
+	h.Bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		panic(boom)
+	}, aquagram.RecoverMiddleware(func(bot *aquagram.Bot, err error) {
+		reported = err
+	}))
+
+	res := h.User(42).Send("hello")
+
+	if !errors.Is(res.Err, boom) {
+		t.Errorf("the panic was not returned, got %v", res.Err)
+	}
+
+	if !errors.Is(reported, boom) {
+		t.Errorf("the panic was not reported, got %v", reported)
+	}
+}
@@ -0,0 +1,223 @@
+package aquagram
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+[OffsetStore] keeps the offset of the [PollingUpdater] between restarts,
+see [NewFileOffsetStore].
+
+The saved offset is the identifier of the first update that was not handled yet.
+*/
+type OffsetStore interface {
+	// Load returns the saved offset, or 0 if none was saved.
+	Load() (int, error)
+
+	Save(offset int) error
+}
+
+// FileOffsetStore saves the offset in a text file.
+type FileOffsetStore struct {
+	Path string
+}
+
+func NewFileOffsetStore(path string) *FileOffsetStore {
+	store := new(FileOffsetStore)
+	store.Path = path
+
+	return store
+}
+
+func (store *FileOffsetStore) Load() (int, error) {
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// Save replaces the file atomically, so a crash never leaves it truncated.
+func (store *FileOffsetStore) Save(offset int) error {
+	return writeFileAtomic(store.Path, []byte(strconv.Itoa(offset)+"\n"))
+}
+
+// writeFileAtomic writes data to a temporary file and then renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+/*
+offsetTracker keeps the offset of the first update whose handlers did not finish,
+so an update is confirmed to Telegram only once it was handled.
+*/
+type offsetTracker struct {
+	mu sync.Mutex
+
+	// identifier of the next update to receive
+	next int
+
+	// time every pending update was received
+	pending map[int]time.Time
+
+	// updates never handled because the bot was stopped, they are received again on restart
+	abandoned map[int]struct{}
+
+	// pending updates older than timeout no longer hold back the offset, if not 0
+	timeout time.Duration
+
+	// closed when a pending update is done
+	progress chan struct{}
+}
+
+func newOffsetTracker(offset int, timeout time.Duration) *offsetTracker {
+	tracker := new(offsetTracker)
+	tracker.next = offset
+	tracker.pending = make(map[int]time.Time)
+	tracker.abandoned = make(map[int]struct{})
+	tracker.timeout = timeout
+	tracker.progress = make(chan struct{})
+
+	return tracker
+}
+
+func (tracker *offsetTracker) offset() int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.offsetLocked()
+}
+
+func (tracker *offsetTracker) offsetLocked() int {
+	offset := tracker.next
+
+	for id, received := range tracker.pending {
+		if tracker.timeout > 0 && time.Since(received) > tracker.timeout {
+			continue
+		}
+
+		offset = min(offset, id)
+	}
+
+	for id := range tracker.abandoned {
+		offset = min(offset, id)
+	}
+
+	return offset
+}
+
+// add marks id as pending, it returns false if id was already received.
+func (tracker *offsetTracker) add(id int) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if id < tracker.next {
+		return false
+	}
+
+	tracker.next = id + 1
+	tracker.pending[id] = time.Now()
+
+	return true
+}
+
+func (tracker *offsetTracker) done(id int) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	delete(tracker.pending, id)
+
+	close(tracker.progress)
+	tracker.progress = make(chan struct{})
+}
+
+// abandon marks id as never handled, the offset does not advance past it.
+func (tracker *offsetTracker) abandon(id int) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	delete(tracker.pending, id)
+	tracker.abandoned[id] = struct{}{}
+
+	close(tracker.progress)
+	tracker.progress = make(chan struct{})
+}
+
+// wait returns once the offset is not offset, timeout elapsed or ctx is done.
+func (tracker *offsetTracker) wait(ctx context.Context, offset int, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	tracker.mu.Lock()
+
+	for tracker.offsetLocked() == offset {
+		progress := tracker.progress
+		tracker.mu.Unlock()
+
+		select {
+		case <-progress:
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		tracker.mu.Lock()
+	}
+
+	tracker.mu.Unlock()
+}
+
+// drain returns once there are no pending updates or ctx is done.
+func (tracker *offsetTracker) drain(ctx context.Context) {
+	tracker.mu.Lock()
+
+	for len(tracker.pending) > 0 {
+		progress := tracker.progress
+		tracker.mu.Unlock()
+
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return
+		}
+
+		tracker.mu.Lock()
+	}
+
+	tracker.mu.Unlock()
+}
@@ -0,0 +1,228 @@
+package aquagram_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestFileOffsetStore(t *testing.T) {
+	store := aquagram.NewFileOffsetStore(filepath.Join(t.TempDir(), "offset"))
+
+	offset, err := store.Load()
+	if err != nil || offset != 0 {
+		t.Fatalf("unexpected offset %d, error %v", offset, err)
+	}
+
+	if err := store.Save(42); err != nil {
+		t.Fatal(err)
+	}
+
+	if offset, err := store.Load(); err != nil || offset != 42 {
+		t.Errorf("unexpected offset %d, error %v", offset, err)
+	}
+}
+
+func TestPollingOffsetStore(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	store := aquagram.NewFileOffsetStore(filepath.Join(t.TempDir(), "offset"))
+
+	bot := server.NewBot()
+
+	started := make(chan int64, 10)
+	release := make(chan struct{})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		started <- message.MessageID
+
+		if message.Text == "slow" {
+			<-release
+		}
+
+		return nil
+	})
+
+	go bot.StartPollingWithOptions(&aquagram.PollingOptions{OffsetStore: store})
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+	chat := aquagramtest.PrivateChat(user)
+
+	slow, err := server.InjectMessage(user, chat, "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	fast, err := server.InjectMessage(user, chat, "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := <-started; id != fast.MessageID {
+		t.Fatalf("unexpected message %d", id)
+	}
+
+	// the slow update must not be confirmed until its handler returns
+	time.Sleep(100 * time.Millisecond)
+
+	if updates := server.PendingUpdates(); len(updates) != 2 || updates[0].Message.MessageID != slow.MessageID {
+		t.Fatalf("unexpected pending updates %d", len(updates))
+	}
+
+	close(release)
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if updates := server.PendingUpdates(); len(updates) != 0 {
+		t.Errorf("%d updates are still pending", len(updates))
+	}
+
+	offset, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if last := server.LastRequest("getUpdates"); offset != last.Int("offset") {
+		t.Errorf("saved offset %d, confirmed %d", offset, last.Int("offset"))
+	}
+
+	if len(started) != 0 {
+		t.Errorf("%d updates were handled twice", len(started))
+	}
+}
+
+type updaterFunc func(ctx context.Context, sink aquagram.UpdateSink) error
+
+func (fn updaterFunc) Run(ctx context.Context, sink aquagram.UpdateSink) error {
+	return fn(ctx, sink)
+}
+
+func TestStoppedUpdateNotAcknowledged(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	bot.Config.Dispatcher = aquagram.NewDispatcher(&aquagram.DispatcherOptions{Workers: 1, QueueSize: 1})
+
+	started := make(chan struct{}, 3)
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		started <- struct{}{}
+		<-bot.Context().Done()
+		return nil
+	})
+
+	var acknowledged [3]atomic.Bool
+	var err error
+
+	updater := updaterFunc(func(ctx context.Context, sink aquagram.UpdateSink) error {
+		for i := range acknowledged {
+			update := &aquagram.Update{
+				UpdateID: i + 1,
+				Message:  &aquagram.Message{MessageID: int64(i + 1), Chat: &aquagram.Chat{ID: 42}},
+			}
+
+			if i == 1 {
+				<-started
+			}
+
+			// the third update waits for room in the queue
+			if i == 2 {
+				time.AfterFunc(100*time.Millisecond, bot.Stop)
+			}
+
+			err = sink(update, func() {
+				acknowledged[i].Store(true)
+			})
+		}
+
+		return nil
+	})
+
+	bot.StartWithUpdater(updater)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !acknowledged[0].Load() || !acknowledged[1].Load() {
+		t.Error("the handled updates were not acknowledged")
+	}
+
+	if acknowledged[2].Load() {
+		t.Error("the update never handled was acknowledged")
+	}
+}
+
+func TestPollingPendingTimeout(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	store := aquagram.NewFileOffsetStore(filepath.Join(t.TempDir(), "offset"))
+
+	bot := server.NewBot()
+
+	handled := make(chan string, 2)
+	release := make(chan struct{})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		handled <- message.Text
+
+		if message.Text == "slow" {
+			<-release
+		}
+
+		return nil
+	})
+
+	go bot.StartPollingWithOptions(&aquagram.PollingOptions{
+		Limit:          1,
+		OffsetStore:    store,
+		PendingTimeout: 200 * time.Millisecond,
+	})
+
+	first := &aquagram.User{ID: 1, FirstName: "John"}
+	second := &aquagram.User{ID: 2, FirstName: "Jane"}
+
+	if _, err := server.InjectMessage(first, aquagramtest.PrivateChat(first), "slow"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-handled
+
+	if _, err := server.InjectMessage(second, aquagramtest.PrivateChat(second), "fast"); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the slow update is returned until it stops holding back the offset
+	select {
+	case text := <-handled:
+		if text != "fast" {
+			t.Errorf("unexpected message %q", text)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Error("the slow handler stalled the polling")
+	}
+
+	close(release)
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,10 +15,8 @@ const (
 
 func ParseRawResult[T any](bot *Bot, data []byte) (T, error) {
 	var res struct {
-		Ok          bool   `json:"ok"`
-		Result      T      `json:"result"`
-		ErrorCode   int    `json:"error_code"`
-		Description string `json:"description"`
+		TelegramResponse
+		Result T `json:"result"`
 	}
 
 	if err := json.Unmarshal(data, &res); err != nil {
@@ -27,7 +25,7 @@ func ParseRawResult[T any](bot *Bot, data []byte) (T, error) {
 
 	if !res.Ok {
 		if res.ErrorCode != 0 {
-			return res.Result, errTgBadRequest(res.ErrorCode, res.Description)
+			return res.Result, newAPIError(res.ErrorCode, res.Description, res.Parameters)
 		}
 
 		return res.Result, errors.New("unknown error parsing raw result: " + string(data))
@@ -39,6 +37,10 @@ func ParseRawResult[T any](bot *Bot, data []byte) (T, error) {
 		message.process(bot)
 	}
 
+	if poll, ok := result.(*Poll); ok {
+		poll.process(bot)
+	}
+
 	return res.Result, nil
 }
 
@@ -0,0 +1,474 @@
+package aquagram
+
+import (
+	"context"
+)
+
+// Currency of the payments in Telegram Stars, used for digital goods and services.
+const CurrencyStars = "XTR"
+
+// https://core.telegram.org/bots/api#invoice
+type Invoice struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	StartParameter string `json:"start_parameter"`
+	Currency       string `json:"currency"`
+	TotalAmount    int    `json:"total_amount"`
+}
+
+// https://core.telegram.org/bots/api#shippingaddress
+type ShippingAddress struct {
+	CountryCode string `json:"country_code"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	PostCode    string `json:"post_code"`
+}
+
+// https://core.telegram.org/bots/api#orderinfo
+type OrderInfo struct {
+	Name            string           `json:"name,omitempty"`
+	PhoneNumber     string           `json:"phone_number,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+}
+
+// https://core.telegram.org/bots/api#shippingoption
+type ShippingOption struct {
+	ID     string         `json:"id"`
+	Title  string         `json:"title"`
+	Prices []LabeledPrice `json:"prices"`
+}
+
+/*
+[SuccessfulPayment] - This object contains basic information about a successful payment.
+
+[SuccessfulPayment]: https://core.telegram.org/bots/api#successfulpayment
+*/
+type SuccessfulPayment struct {
+	Currency                string     `json:"currency"`
+	TotalAmount             int        `json:"total_amount"`
+	InvoicePayload          string     `json:"invoice_payload"`
+	ShippingOptionID        string     `json:"shipping_option_id,omitempty"`
+	OrderInfo               *OrderInfo `json:"order_info,omitempty"`
+	TelegramPaymentChargeID string     `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string     `json:"provider_payment_charge_id"`
+}
+
+/*
+[ShippingQuery] - This object contains information about an incoming shipping query.
+
+It is received only for invoices with flexible price.
+
+[ShippingQuery]: https://core.telegram.org/bots/api#shippingquery
+*/
+type ShippingQuery struct {
+	Bot *Bot `json:"-"`
+
+	ID              string           `json:"id"`
+	From            *User            `json:"from"`
+	InvoicePayload  string           `json:"invoice_payload"`
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+}
+
+/*
+[Answer] is an alias for [AnswerShippingQuery]
+*/
+func (query *ShippingQuery) Answer(ok bool, params *AnswerShippingQueryParams) error {
+	return query.Bot.AnswerShippingQuery(query.ID, ok, params)
+}
+
+func (query *ShippingQuery) GetMessage() *Message {
+	return nil
+}
+
+func (query *ShippingQuery) GetFrom() *User {
+	return query.From
+}
+
+func (query *ShippingQuery) GetChat() *Chat {
+	return nil
+}
+
+func (query *ShippingQuery) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (query *ShippingQuery) GetEntities() []*MessageEntity {
+	return nil
+}
+
+/*
+[PreCheckoutQuery] - This object contains information about an incoming pre-checkout query.
+
+[PreCheckoutQuery]: https://core.telegram.org/bots/api#precheckoutquery
+*/
+type PreCheckoutQuery struct {
+	Bot *Bot `json:"-"`
+
+	ID               string     `json:"id"`
+	From             *User      `json:"from"`
+	Currency         string     `json:"currency"`
+	TotalAmount      int        `json:"total_amount"`
+	InvoicePayload   string     `json:"invoice_payload"`
+	ShippingOptionID string     `json:"shipping_option_id,omitempty"`
+	OrderInfo        *OrderInfo `json:"order_info,omitempty"`
+}
+
+/*
+[Answer] is an alias for [AnswerPreCheckoutQuery]
+*/
+func (query *PreCheckoutQuery) Answer(ok bool, params *AnswerPreCheckoutQueryParams) error {
+	return query.Bot.AnswerPreCheckoutQuery(query.ID, ok, params)
+}
+
+func (query *PreCheckoutQuery) GetMessage() *Message {
+	return nil
+}
+
+func (query *PreCheckoutQuery) GetFrom() *User {
+	return query.From
+}
+
+func (query *PreCheckoutQuery) GetChat() *Chat {
+	return nil
+}
+
+func (query *PreCheckoutQuery) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (query *PreCheckoutQuery) GetEntities() []*MessageEntity {
+	return nil
+}
+
+// InvoiceParams are the parameters shared by [SendInvoice] and [CreateInvoiceLink].
+type InvoiceParams struct {
+	Title       string `json:"title"`       // 1-32 characters
+	Description string `json:"description"` // 1-255 characters
+	Payload     string `json:"payload"`     // 1-128 bytes, not displayed to the user
+
+	// Empty for payments in Telegram Stars
+	ProviderToken string `json:"provider_token,omitempty"`
+
+	Currency                  string         `json:"currency"`
+	Prices                    []LabeledPrice `json:"prices"` // only one price for payments in Telegram Stars
+	MaxTipAmount              int            `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int          `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              string         `json:"provider_data,omitempty"`
+	PhotoURL                  string         `json:"photo_url,omitempty"`
+	PhotoSize                 int            `json:"photo_size,omitempty"`
+	PhotoWidth                int            `json:"photo_width,omitempty"`
+	PhotoHeight               int            `json:"photo_height,omitempty"`
+	NeedName                  bool           `json:"need_name,omitempty"`
+	NeedPhoneNumber           bool           `json:"need_phone_number,omitempty"`
+	NeedEmail                 bool           `json:"need_email,omitempty"`
+	NeedShippingAddress       bool           `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool           `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool           `json:"send_email_to_provider,omitempty"`
+	IsFlexible                bool           `json:"is_flexible,omitempty"`
+}
+
+type SendInvoiceParams struct {
+	InvoiceParams
+	ChatID              string                `json:"chat_id"`
+	MessageThreadID     int64                 `json:"message_thread_id,omitempty"`
+	StartParameter      string                `json:"start_parameter,omitempty"`
+	DisableNotification bool                  `json:"disable_notification,omitempty"`
+	ProtectContent      bool                  `json:"protect_content,omitempty"`
+	MessageEffectID     string                `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters      `json:"reply_parameters,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+/*
+[SendInvoice] wraps [SendInvoiceWithContext] using the default bot context.
+*/
+func (bot *Bot) SendInvoice(chatID string, title string, description string, payload string, currency string, prices []LabeledPrice, params *SendInvoiceParams) (*Message, error) {
+	return bot.SendInvoiceWithContext(bot.stopContext, chatID, title, description, payload, currency, prices, params)
+}
+
+/*
+[sendInvoice] - Use this method to send invoices.
+
+On success, the sent Message is returned.
+
+[sendInvoice]: https://core.telegram.org/bots/api#sendinvoice
+*/
+func (bot *Bot) SendInvoiceWithContext(ctx context.Context, chatID string, title string, description string, payload string, currency string, prices []LabeledPrice, params *SendInvoiceParams) (*Message, error) {
+	if params == nil {
+		params = new(SendInvoiceParams)
+	}
+
+	params.ChatID = ParseChatID(chatID)
+	params.Title = title
+	params.Description = description
+	params.Payload = payload
+	params.Currency = currency
+	params.Prices = prices
+
+	data, err := bot.Raw(ctx, "sendInvoice", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*Message](bot, data)
+}
+
+type CreateInvoiceLinkParams struct {
+	InvoiceParams
+	BusinessConnectionID string `json:"business_connection_id,omitempty"`
+	SubscriptionPeriod   int    `json:"subscription_period,omitempty"` // seconds, only 2592000 is supported
+}
+
+/*
+[CreateInvoiceLink] wraps [CreateInvoiceLinkWithContext] using the default bot context.
+*/
+func (bot *Bot) CreateInvoiceLink(title string, description string, payload string, currency string, prices []LabeledPrice, params *CreateInvoiceLinkParams) (string, error) {
+	return bot.CreateInvoiceLinkWithContext(bot.stopContext, title, description, payload, currency, prices, params)
+}
+
+/*
+[createInvoiceLink] - Use this method to create a link for an invoice.
+
+On success, the created invoice link is returned.
+
+[createInvoiceLink]: https://core.telegram.org/bots/api#createinvoicelink
+*/
+func (bot *Bot) CreateInvoiceLinkWithContext(ctx context.Context, title string, description string, payload string, currency string, prices []LabeledPrice, params *CreateInvoiceLinkParams) (string, error) {
+	if params == nil {
+		params = new(CreateInvoiceLinkParams)
+	}
+
+	params.Title = title
+	params.Description = description
+	params.Payload = payload
+	params.Currency = currency
+	params.Prices = prices
+
+	data, err := bot.Raw(ctx, "createInvoiceLink", params)
+	if err != nil {
+		return EmptyString, err
+	}
+
+	return ParseRawResult[string](bot, data)
+}
+
+type AnswerShippingQueryParams struct {
+	ShippingQueryID string           `json:"shipping_query_id"`
+	OK              bool             `json:"ok"`
+	ShippingOptions []ShippingOption `json:"shipping_options,omitempty"` // required if OK is true
+	ErrorMessage    string           `json:"error_message,omitempty"`    // required if OK is false
+}
+
+/*
+[AnswerShippingQuery] wraps [AnswerShippingQueryWithContext] using the default bot context.
+*/
+func (bot *Bot) AnswerShippingQuery(shippingQueryID string, ok bool, params *AnswerShippingQueryParams) error {
+	return bot.AnswerShippingQueryWithContext(bot.stopContext, shippingQueryID, ok, params)
+}
+
+/*
+[answerShippingQuery] - If you sent an invoice requesting a shipping address and the parameter is_flexible was specified,
+the Bot API will send an Update with a shipping_query field to the bot. Use this method to reply to shipping queries.
+
+[answerShippingQuery]: https://core.telegram.org/bots/api#answershippingquery
+*/
+func (bot *Bot) AnswerShippingQueryWithContext(ctx context.Context, shippingQueryID string, ok bool, params *AnswerShippingQueryParams) error {
+	if params == nil {
+		params = new(AnswerShippingQueryParams)
+	}
+
+	params.ShippingQueryID = shippingQueryID
+	params.OK = ok
+
+	data, err := bot.Raw(ctx, "answerShippingQuery", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+type AnswerPreCheckoutQueryParams struct {
+	PreCheckoutQueryID string `json:"pre_checkout_query_id"`
+	OK                 bool   `json:"ok"`
+	ErrorMessage       string `json:"error_message,omitempty"` // required if OK is false
+}
+
+/*
+[AnswerPreCheckoutQuery] wraps [AnswerPreCheckoutQueryWithContext] using the default bot context.
+*/
+func (bot *Bot) AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, params *AnswerPreCheckoutQueryParams) error {
+	return bot.AnswerPreCheckoutQueryWithContext(bot.stopContext, preCheckoutQueryID, ok, params)
+}
+
+/*
+[answerPreCheckoutQuery] - Once the user has confirmed their payment and shipping details,
+the Bot API sends the final confirmation in the form of an Update with the field pre_checkout_query.
+Use this method to respond to such pre-checkout queries.
+
+The pre-checkout query must be answered within 10 seconds after it was sent.
+
+[answerPreCheckoutQuery]: https://core.telegram.org/bots/api#answerprecheckoutquery
+*/
+func (bot *Bot) AnswerPreCheckoutQueryWithContext(ctx context.Context, preCheckoutQueryID string, ok bool, params *AnswerPreCheckoutQueryParams) error {
+	if params == nil {
+		params = new(AnswerPreCheckoutQueryParams)
+	}
+
+	params.PreCheckoutQueryID = preCheckoutQueryID
+	params.OK = ok
+
+	data, err := bot.Raw(ctx, "answerPreCheckoutQuery", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+type RefundStarPaymentParams struct {
+	UserID                  int64  `json:"user_id"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+}
+
+/*
+[RefundStarPayment] wraps [RefundStarPaymentWithContext] using the default bot context.
+*/
+func (bot *Bot) RefundStarPayment(userID int64, telegramPaymentChargeID string) error {
+	return bot.RefundStarPaymentWithContext(bot.stopContext, userID, telegramPaymentChargeID)
+}
+
+/*
+[refundStarPayment] - Refunds a successful payment in Telegram Stars.
+
+[refundStarPayment]: https://core.telegram.org/bots/api#refundstarpayment
+*/
+func (bot *Bot) RefundStarPaymentWithContext(ctx context.Context, userID int64, telegramPaymentChargeID string) error {
+	params := new(RefundStarPaymentParams)
+	params.UserID = userID
+	params.TelegramPaymentChargeID = telegramPaymentChargeID
+
+	data, err := bot.Raw(ctx, "refundStarPayment", params)
+	if err != nil {
+		return err
+	}
+
+	success, err := ParseRawResult[bool](bot, data)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		return ErrExpectedTrue
+	}
+
+	return nil
+}
+
+// https://core.telegram.org/bots/api#transactionpartner
+type TransactionPartner struct {
+	// "user", "fragment", "telegram_ads", "telegram_api" or "other"
+	Type string `json:"type"`
+
+	// Only for the "user" type
+	User           *User  `json:"user,omitempty"`
+	InvoicePayload string `json:"invoice_payload,omitempty"`
+}
+
+/*
+[StarTransaction] - Describes a Telegram Star transaction.
+
+[StarTransaction]: https://core.telegram.org/bots/api#startransaction
+*/
+type StarTransaction struct {
+	ID     string `json:"id"`
+	Amount int    `json:"amount"`
+	Date   int64  `json:"date"`
+
+	// Only for incoming transactions
+	Source *TransactionPartner `json:"source,omitempty"`
+
+	// Only for outgoing transactions
+	Receiver *TransactionPartner `json:"receiver,omitempty"`
+}
+
+// https://core.telegram.org/bots/api#startransactions
+type StarTransactions struct {
+	Transactions []*StarTransaction `json:"transactions"`
+}
+
+type GetStarTransactionsParams struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"` // 1-100, defaults to 100
+}
+
+/*
+[GetStarTransactions] wraps [GetStarTransactionsWithContext] using the default bot context.
+*/
+func (bot *Bot) GetStarTransactions(params *GetStarTransactionsParams) (*StarTransactions, error) {
+	return bot.GetStarTransactionsWithContext(bot.stopContext, params)
+}
+
+/*
+[getStarTransactions] - Returns the bot's Telegram Star transactions in chronological order.
+
+[getStarTransactions]: https://core.telegram.org/bots/api#getstartransactions
+*/
+func (bot *Bot) GetStarTransactionsWithContext(ctx context.Context, params *GetStarTransactionsParams) (*StarTransactions, error) {
+	if params == nil {
+		params = new(GetStarTransactionsParams)
+	}
+
+	data, err := bot.Raw(ctx, "getStarTransactions", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*StarTransactions](bot, data)
+}
+
+func (bot *Bot) OnShippingQuery(handler HandlerFunc[*ShippingQuery], middlewares ...Middleware) *Handler {
+	queryHandler := new(Handler)
+	queryHandler.Middlewares = middlewares
+	queryHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnShippingQuery, queryHandler)
+}
+
+func (bot *Bot) OnPreCheckoutQuery(handler HandlerFunc[*PreCheckoutQuery], middlewares ...Middleware) *Handler {
+	queryHandler := new(Handler)
+	queryHandler.Middlewares = middlewares
+	queryHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnPreCheckoutQuery, queryHandler)
+}
+
+// OnSuccessfulPayment registers handler for the service messages about successful payments.
+func (bot *Bot) OnSuccessfulPayment(handler HandlerFunc[*Message], middlewares ...Middleware) *Handler {
+	paymentHandler := new(Handler)
+	paymentHandler.Middlewares = middlewares
+	paymentHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnSuccessfulPayment, paymentHandler)
+}
@@ -0,0 +1,94 @@
+package aquagram_test
+
+import (
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestPayments(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	user := h.User(42)
+
+	h.Bot.OnCommand("buy", func(bot *aquagram.Bot, message *aquagram.Message) error {
+		prices := []aquagram.LabeledPrice{{Label: "Sticker pack", Amount: 50}}
+
+		_, err := bot.SendInvoice(aquagram.ChatID(message.Chat.ID), "Stickers", "A sticker pack", "pack-1", aquagram.CurrencyStars, prices, nil)
+		return err
+	})
+
+	h.Bot.OnPreCheckoutQuery(func(bot *aquagram.Bot, query *aquagram.PreCheckoutQuery) error {
+		if query.InvoicePayload != "pack-1" {
+			return query.Answer(false, &aquagram.AnswerPreCheckoutQueryParams{ErrorMessage: "unknown product"})
+		}
+
+		return query.Answer(true, nil)
+	})
+
+	var payments []*aquagram.SuccessfulPayment
+
+	h.Bot.OnSuccessfulPayment(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		payments = append(payments, message.SuccessfulPayment)
+		return bot.RefundStarPayment(message.From.ID, message.SuccessfulPayment.TelegramPaymentChargeID)
+	})
+
+	res := user.Send("/buy")
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	messages := res.Messages()
+	if len(messages) != 1 || messages[0].Invoice == nil {
+		t.Fatalf("the invoice was not sent, messages %+v", messages)
+	}
+
+	if invoice := messages[0].Invoice; invoice.Currency != "XTR" || invoice.TotalAmount != 50 {
+		t.Errorf("unexpected invoice %+v", invoice)
+	}
+
+	res = h.Dispatch(&aquagram.Update{
+		PreCheckoutQuery: &aquagram.PreCheckoutQuery{
+			ID:             "1",
+			From:           user.User,
+			Currency:       aquagram.CurrencyStars,
+			TotalAmount:    50,
+			InvoicePayload: "pack-1",
+		},
+	})
+
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if calls := res.Calls("answerPreCheckoutQuery"); len(calls) != 1 || !calls[0].Bool("ok") || calls[0].String("pre_checkout_query_id") != "1" {
+		t.Errorf("the pre-checkout query was not answered")
+	}
+
+	res = h.Dispatch(&aquagram.Update{
+		Message: &aquagram.Message{
+			MessageID: 100,
+			From:      user.User,
+			Chat:      user.PrivateChat(),
+			SuccessfulPayment: &aquagram.SuccessfulPayment{
+				Currency:                aquagram.CurrencyStars,
+				TotalAmount:             50,
+				InvoicePayload:          "pack-1",
+				TelegramPaymentChargeID: "charge",
+			},
+		},
+	})
+
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if len(payments) != 1 {
+		t.Fatalf("expected 1 payment, got %d", len(payments))
+	}
+
+	if calls := res.Calls("refundStarPayment"); len(calls) != 1 || calls[0].Int64("user_id") != 42 || calls[0].String("telegram_payment_charge_id") != "charge" {
+		t.Errorf("the payment was not refunded")
+	}
+}
@@ -0,0 +1,453 @@
+package aquagram
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type PollType string
+
+const (
+	PollTypeRegular PollType = "regular"
+	PollTypeQuiz    PollType = "quiz"
+)
+
+// https://core.telegram.org/bots/api#polloption
+type PollOption struct {
+	Text         string           `json:"text"`
+	TextEntities []*MessageEntity `json:"text_entities,omitempty"`
+	VoterCount   int              `json:"voter_count"`
+}
+
+// https://core.telegram.org/bots/api#inputpolloption
+type InputPollOption struct {
+	Text          string           `json:"text"` // 1-100 characters
+	TextParseMode ParseMode        `json:"text_parse_mode,omitempty"`
+	TextEntities  []*MessageEntity `json:"text_entities,omitempty"`
+}
+
+/*
+[Poll] - This object contains information about a poll.
+
+[Poll]: https://core.telegram.org/bots/api#poll
+*/
+type Poll struct {
+	Bot *Bot `json:"-"`
+
+	ID                    string           `json:"id"`
+	Question              string           `json:"question"`
+	QuestionEntities      []*MessageEntity `json:"question_entities,omitempty"`
+	Options               []PollOption     `json:"options"`
+	TotalVoterCount       int              `json:"total_voter_count"`
+	IsClosed              bool             `json:"is_closed"`
+	IsAnonymous           bool             `json:"is_anonymous"`
+	Type                  PollType         `json:"type"`
+	AllowsMultipleAnswers bool             `json:"allows_multiple_answers"`
+
+	// Only in quizzes sent by the bot or closed
+	CorrectOptionID *int `json:"correct_option_id,omitempty"`
+
+	Explanation         string           `json:"explanation,omitempty"`
+	ExplanationEntities []*MessageEntity `json:"explanation_entities,omitempty"`
+	OpenPeriod          int              `json:"open_period,omitempty"`
+	CloseDate           int64            `json:"close_date,omitempty"`
+}
+
+func (poll *Poll) GetMessage() *Message {
+	return nil
+}
+
+func (poll *Poll) GetFrom() *User {
+	return nil
+}
+
+func (poll *Poll) GetChat() *Chat {
+	return nil
+}
+
+func (poll *Poll) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (poll *Poll) GetEntities() []*MessageEntity {
+	return nil
+}
+
+/*
+[PollAnswer] - This object represents an answer of a user in a non-anonymous poll.
+
+[PollAnswer]: https://core.telegram.org/bots/api#pollanswer
+*/
+type PollAnswer struct {
+	Bot *Bot `json:"-"`
+
+	PollID string `json:"poll_id"`
+
+	// The chat that changed the answer, if the voter is anonymous
+	VoterChat *Chat `json:"voter_chat,omitempty"`
+
+	// The user that changed the answer, if the voter isn't anonymous
+	User *User `json:"user,omitempty"`
+
+	// Empty if the vote was retracted
+	OptionIDs []int `json:"option_ids"`
+}
+
+func (answer *PollAnswer) GetMessage() *Message {
+	return nil
+}
+
+func (answer *PollAnswer) GetFrom() *User {
+	return answer.User
+}
+
+func (answer *PollAnswer) GetChat() *Chat {
+	return answer.VoterChat
+}
+
+func (answer *PollAnswer) GetCallbackQuery() *CallbackQuery {
+	return nil
+}
+
+func (answer *PollAnswer) GetEntities() []*MessageEntity {
+	return nil
+}
+
+type SendPollParams struct {
+	BusinessConnectionID string            `json:"business_connection_id,omitempty"`
+	ChatID               string            `json:"chat_id"`
+	MessageThreadID      int64             `json:"message_thread_id,omitempty"`
+	Question             string            `json:"question"` // 1-300 characters
+	QuestionParseMode    ParseMode         `json:"question_parse_mode,omitempty"`
+	QuestionEntities     []*MessageEntity  `json:"question_entities,omitempty"`
+	Options              []InputPollOption `json:"options"` // 2-10 options
+
+	// By default is true
+	IsAnonymous *bool `json:"is_anonymous,omitempty"`
+
+	Type                  PollType `json:"type,omitempty"`
+	AllowsMultipleAnswers bool     `json:"allows_multiple_answers,omitempty"`
+
+	// Required for quizzes
+	CorrectOptionID *int `json:"correct_option_id,omitempty"`
+
+	// Shown when the user chooses an incorrect answer in a quiz, 0-200 characters
+	Explanation          string           `json:"explanation,omitempty"`
+	ExplanationParseMode ParseMode        `json:"explanation_parse_mode,omitempty"`
+	ExplanationEntities  []*MessageEntity `json:"explanation_entities,omitempty"`
+
+	// Time the poll will be active after creation, 5-600 seconds
+	OpenPeriod time.Duration `json:"-"`
+
+	CloseDate           int64            `json:"close_date,omitempty"`
+	IsClosed            bool             `json:"is_closed,omitempty"`
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	MessageEffectID     string           `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyMarkup         ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// MarshalJSON sends OpenPeriod in seconds.
+func (params *SendPollParams) MarshalJSON() ([]byte, error) {
+	type plain SendPollParams
+
+	return json.Marshal(struct {
+		*plain
+		OpenPeriod int64 `json:"open_period,omitempty"`
+	}{
+		plain:      (*plain)(params),
+		OpenPeriod: int64(params.OpenPeriod.Seconds()),
+	})
+}
+
+/*
+[SendPoll] wraps [SendPollWithContext] using the default bot context.
+*/
+func (bot *Bot) SendPoll(chatID string, question string, options []string, params *SendPollParams) (*Message, error) {
+	return bot.SendPollWithContext(bot.stopContext, chatID, question, options, params)
+}
+
+/*
+[sendPoll] - Use this method to send a native poll.
+
+On success, the sent Message is returned.
+
+[sendPoll]: https://core.telegram.org/bots/api#sendpoll
+*/
+func (bot *Bot) SendPollWithContext(ctx context.Context, chatID string, question string, options []string, params *SendPollParams) (*Message, error) {
+	if params == nil {
+		params = new(SendPollParams)
+	}
+
+	params.ChatID = ParseChatID(chatID)
+	params.Question = question
+
+	if options != nil {
+		params.Options = make([]InputPollOption, len(options))
+
+		for i, option := range options {
+			params.Options[i].Text = option
+		}
+	}
+
+	data, err := bot.Raw(ctx, "sendPoll", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*Message](bot, data)
+}
+
+/*
+[SendQuiz] wraps [SendQuizWithContext] using the default bot context.
+*/
+func (bot *Bot) SendQuiz(chatID string, question string, options []string, correctOptionID int, params *SendPollParams) (*Message, error) {
+	return bot.SendQuizWithContext(bot.stopContext, chatID, question, options, correctOptionID, params)
+}
+
+/*
+[SendQuizWithContext] wraps [SendPollWithContext] sending a quiz
+with the correct option at correctOptionID.
+*/
+func (bot *Bot) SendQuizWithContext(ctx context.Context, chatID string, question string, options []string, correctOptionID int, params *SendPollParams) (*Message, error) {
+	if params == nil {
+		params = new(SendPollParams)
+	}
+
+	params.Type = PollTypeQuiz
+	params.CorrectOptionID = &correctOptionID
+
+	return bot.SendPollWithContext(ctx, chatID, question, options, params)
+}
+
+type StopPollParams struct {
+	BusinessConnectionID string                `json:"business_connection_id,omitempty"`
+	ChatID               string                `json:"chat_id"`
+	MessageID            int64                 `json:"message_id"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+/*
+[StopPoll] is an alias for [Bot.StopPoll].
+*/
+func (message *Message) StopPoll(params *StopPollParams) (*Poll, error) {
+	return message.Bot.StopPoll(ChatID(message.Chat.ID), message.MessageID, params)
+}
+
+/*
+[StopPoll] wraps [StopPollWithContext] using the default bot context.
+*/
+func (bot *Bot) StopPoll(chatID string, messageID int64, params *StopPollParams) (*Poll, error) {
+	return bot.StopPollWithContext(bot.stopContext, chatID, messageID, params)
+}
+
+/*
+[stopPoll] - Use this method to stop a poll which was sent by the bot.
+
+On success, the stopped Poll is returned.
+
+[stopPoll]: https://core.telegram.org/bots/api#stoppoll
+*/
+func (bot *Bot) StopPollWithContext(ctx context.Context, chatID string, messageID int64, params *StopPollParams) (*Poll, error) {
+	if params == nil {
+		params = new(StopPollParams)
+	}
+
+	params.ChatID = ParseChatID(chatID)
+	params.MessageID = messageID
+
+	data, err := bot.Raw(ctx, "stopPoll", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRawResult[*Poll](bot, data)
+}
+
+func (bot *Bot) OnPoll(handler HandlerFunc[*Poll], middlewares ...Middleware) *Handler {
+	pollHandler := new(Handler)
+	pollHandler.Middlewares = middlewares
+	pollHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnPoll, pollHandler)
+}
+
+func (bot *Bot) OnPollAnswer(handler HandlerFunc[*PollAnswer], middlewares ...Middleware) *Handler {
+	answerHandler := new(Handler)
+	answerHandler.Middlewares = middlewares
+	answerHandler.Callback = handlerFunc(handler)
+
+	return Register(bot, OnPollAnswer, answerHandler)
+}
+
+// PollResults are the vote counts of a poll known by a [PollTracker].
+type PollResults struct {
+	PollID      string
+	Counts      []int // votes of every option
+	TotalVoters int
+	IsClosed    bool
+}
+
+/*
+PollTracker keeps the live vote counts of polls,
+aggregating poll updates and the answers of the users:
+
+	tracker := aquagram.NewPollTracker()
+
+	bot.OnPoll(tracker.HandlePoll)
+	bot.OnPollAnswer(tracker.HandlePollAnswer)
+
+Poll updates, received only for polls sent by the bot, carry the counts of all the votes
+and are the source of truth once one was received. Until then, the counts are
+tallied from the answers, received only for non-anonymous polls.
+
+Telegram sends both in no particular order, so an answer never changes
+the counts of a poll with a known snapshot, it only updates the choice of its voter.
+*/
+type PollTracker struct {
+	mu    sync.Mutex
+	polls map[string]*trackedPoll
+}
+
+type trackedPoll struct {
+	// last poll update, nil if none was received
+	snapshot *PollResults
+
+	// options chosen by every voter
+	votes map[int64][]int
+}
+
+func NewPollTracker() *PollTracker {
+	tracker := new(PollTracker)
+	tracker.polls = make(map[string]*trackedPoll)
+
+	return tracker
+}
+
+func (tracker *PollTracker) poll(pollID string) *trackedPoll {
+	poll, ok := tracker.polls[pollID]
+	if !ok {
+		poll = new(trackedPoll)
+		poll.votes = make(map[int64][]int)
+
+		tracker.polls[pollID] = poll
+	}
+
+	return poll
+}
+
+// Track replaces the counts of poll, e.g. with [Message.Poll] after [Bot.SendPoll].
+func (tracker *PollTracker) Track(poll *Poll) {
+	snapshot := new(PollResults)
+	snapshot.PollID = poll.ID
+	snapshot.Counts = make([]int, len(poll.Options))
+	snapshot.TotalVoters = poll.TotalVoterCount
+	snapshot.IsClosed = poll.IsClosed
+
+	for i, option := range poll.Options {
+		snapshot.Counts[i] = option.VoterCount
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.poll(poll.ID).snapshot = snapshot
+}
+
+// HandlePoll is a [HandlerFunc] tracking the received polls.
+func (tracker *PollTracker) HandlePoll(bot *Bot, poll *Poll) error {
+	tracker.Track(poll)
+	return nil
+}
+
+// HandlePollAnswer is a [HandlerFunc] recording the choices of the voters.
+func (tracker *PollTracker) HandlePollAnswer(bot *Bot, answer *PollAnswer) error {
+	var voterID int64
+
+	switch {
+	case answer.User != nil:
+		voterID = answer.User.ID
+	case answer.VoterChat != nil:
+		voterID = answer.VoterChat.ID
+	}
+
+	options := make([]int, 0, len(answer.OptionIDs))
+
+	for _, option := range answer.OptionIDs {
+		if option >= 0 {
+			options = append(options, option)
+		}
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	poll := tracker.poll(answer.PollID)
+
+	if len(options) == 0 {
+		delete(poll.votes, voterID)
+		return nil
+	}
+
+	poll.votes[voterID] = options
+
+	return nil
+}
+
+// Results returns the counts of the poll identified by pollID, or false if it is not tracked.
+func (tracker *PollTracker) Results(pollID string) (*PollResults, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	poll, ok := tracker.polls[pollID]
+	if !ok {
+		return nil, false
+	}
+
+	if poll.snapshot != nil {
+		results := *poll.snapshot
+		results.Counts = append([]int(nil), poll.snapshot.Counts...)
+
+		return &results, true
+	}
+
+	results := new(PollResults)
+	results.PollID = pollID
+	results.TotalVoters = len(poll.votes)
+
+	for _, options := range poll.votes {
+		for _, option := range options {
+			for option >= len(results.Counts) {
+				results.Counts = append(results.Counts, 0)
+			}
+
+			results.Counts[option]++
+		}
+	}
+
+	return results, true
+}
+
+// Vote returns the options chosen by a voter, from its last answer, or nil.
+func (tracker *PollTracker) Vote(pollID string, voterID int64) []int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	poll, ok := tracker.polls[pollID]
+	if !ok {
+		return nil
+	}
+
+	return append([]int(nil), poll.votes[voterID]...)
+}
+
+// Forget stops tracking the poll identified by pollID.
+func (tracker *PollTracker) Forget(pollID string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	delete(tracker.polls, pollID)
+}
@@ -0,0 +1,168 @@
+package aquagram_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestSendQuiz(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	server.AddChat(&aquagram.Chat{ID: 42, Type: aquagram.ChatTypePrivate})
+
+	bot := server.NewBot()
+
+	notAnonymous := false
+
+	message, err := bot.SendQuiz("42", "2 + 2", []string{"3", "4", "5"}, 1, &aquagram.SendPollParams{
+		IsAnonymous: &notAnonymous,
+		Explanation: "basic math",
+		OpenPeriod:  time.Minute,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poll := message.Poll
+	if poll == nil || poll.Type != aquagram.PollTypeQuiz || poll.IsAnonymous || len(poll.Options) != 3 {
+		t.Fatalf("unexpected poll %+v", poll)
+	}
+
+	if poll.CorrectOptionID == nil || *poll.CorrectOptionID != 1 || poll.Explanation != "basic math" {
+		t.Errorf("unexpected quiz answer %+v", poll)
+	}
+
+	if poll.OpenPeriod != 60 {
+		t.Errorf("unexpected open period %d", poll.OpenPeriod)
+	}
+
+	if request := server.LastRequest("sendPoll"); request.String("correct_option_id") != "1" {
+		t.Errorf("correct_option_id was not sent, params %+v", request.Params)
+	}
+
+	stopped, err := message.StopPoll(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stopped.IsClosed || stopped.ID != poll.ID {
+		t.Errorf("unexpected stopped poll %+v", stopped)
+	}
+
+	if _, err := message.StopPoll(nil); err == nil {
+		t.Error("a closed poll was stopped")
+	}
+}
+
+func TestPollTracker(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	tracker := aquagram.NewPollTracker()
+
+	h.Bot.OnPoll(tracker.HandlePoll)
+	h.Bot.OnPollAnswer(tracker.HandlePollAnswer)
+
+	answers := []*aquagram.PollAnswer{
+		{PollID: "poll", User: h.User(1).User, OptionIDs: []int{0}},
+		{PollID: "poll", User: h.User(2).User, OptionIDs: []int{0}},
+		{PollID: "poll", User: h.User(3).User, OptionIDs: []int{2}},
+
+		// vote changed
+		{PollID: "poll", User: h.User(1).User, OptionIDs: []int{1}},
+
+		// vote retracted
+		{PollID: "poll", User: h.User(3).User, OptionIDs: []int{}},
+
+		// invalid options are ignored
+		{PollID: "poll", User: h.User(4).User, OptionIDs: []int{-1}},
+	}
+
+	for _, answer := range answers {
+		if res := h.Dispatch(&aquagram.Update{PollAnswer: answer}); res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+
+	results, ok := tracker.Results("poll")
+	if !ok {
+		t.Fatal("the poll is not tracked")
+	}
+
+	if !reflect.DeepEqual(results.Counts, []int{1, 1}) || results.TotalVoters != 2 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	if vote := tracker.Vote("poll", 1); !reflect.DeepEqual(vote, []int{1}) {
+		t.Errorf("unexpected vote %v", vote)
+	}
+
+	h.Dispatch(&aquagram.Update{
+		Poll: &aquagram.Poll{
+			ID:              "poll",
+			Options:         []aquagram.PollOption{{VoterCount: 4}, {VoterCount: 1}, {VoterCount: 0}},
+			TotalVoterCount: 5,
+			IsClosed:        true,
+		},
+	})
+
+	results, _ = tracker.Results("poll")
+	if !reflect.DeepEqual(results.Counts, []int{4, 1, 0}) || results.TotalVoters != 5 || !results.IsClosed {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+func TestPollTrackerSnapshotFirst(t *testing.T) {
+	h := aquagramtest.NewHarness(t)
+
+	tracker := aquagram.NewPollTracker()
+
+	h.Bot.OnPoll(tracker.HandlePoll)
+	h.Bot.OnPollAnswer(tracker.HandlePollAnswer)
+
+	user := h.User(1)
+
+	// the poll update already counts the vote of its answer
+	h.Dispatch(&aquagram.Update{
+		Poll: &aquagram.Poll{
+			ID:              "poll",
+			Options:         []aquagram.PollOption{{VoterCount: 0}, {VoterCount: 1}},
+			TotalVoterCount: 1,
+		},
+	})
+
+	h.Dispatch(&aquagram.Update{
+		PollAnswer: &aquagram.PollAnswer{PollID: "poll", User: user.User, OptionIDs: []int{1}},
+	})
+
+	results, _ := tracker.Results("poll")
+	if !reflect.DeepEqual(results.Counts, []int{0, 1}) || results.TotalVoters != 1 {
+		t.Errorf("the vote was counted twice, results %+v", results)
+	}
+
+	// retracted, the counts change with the next poll update
+	h.Dispatch(&aquagram.Update{
+		PollAnswer: &aquagram.PollAnswer{PollID: "poll", User: user.User, OptionIDs: []int{}},
+	})
+
+	h.Dispatch(&aquagram.Update{
+		Poll: &aquagram.Poll{
+			ID:      "poll",
+			Options: []aquagram.PollOption{{VoterCount: 0}, {VoterCount: 0}},
+		},
+	})
+
+	results, _ = tracker.Results("poll")
+	if !reflect.DeepEqual(results.Counts, []int{0, 0}) || results.TotalVoters != 0 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	if vote := tracker.Vote("poll", user.ID); vote != nil {
+		t.Errorf("the retracted vote is still recorded, %v", vote)
+	}
+}
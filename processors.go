@@ -14,6 +14,10 @@ func (message *Message) process(bot *Bot) {
 	if message.ReplyToMessage != nil {
 		message.ReplyToMessage.process(bot)
 	}
+
+	if message.Poll != nil {
+		message.Poll.process(bot)
+	}
 }
 
 func (callback *CallbackQuery) process(bot *Bot) {
@@ -23,3 +27,35 @@ func (callback *CallbackQuery) process(bot *Bot) {
 		callback.Message.process(bot)
 	}
 }
+
+func (query *InlineQuery) process(bot *Bot) {
+	query.Bot = bot
+}
+
+func (result *ChosenInlineResult) process(bot *Bot) {
+	result.Bot = bot
+}
+
+func (query *ShippingQuery) process(bot *Bot) {
+	query.Bot = bot
+}
+
+func (query *PreCheckoutQuery) process(bot *Bot) {
+	query.Bot = bot
+}
+
+func (poll *Poll) process(bot *Bot) {
+	poll.Bot = bot
+}
+
+func (answer *PollAnswer) process(bot *Bot) {
+	answer.Bot = bot
+}
+
+func (update *ChatMemberUpdated) process(bot *Bot) {
+	update.Bot = bot
+}
+
+func (request *ChatJoinRequest) process(bot *Bot) {
+	request.Bot = bot
+}
@@ -0,0 +1,286 @@
+package aquagram
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter delays outgoing API requests, see [RateLimiter].
+type Limiter interface {
+	// Wait blocks until a request for method can be sent to chatID,
+	// or returns an error if ctx is done before.
+	Wait(ctx context.Context, method string, chatID string) error
+}
+
+// Limit allows Count requests for each Period.
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+func (limit Limit) interval() time.Duration {
+	return limit.Period / time.Duration(limit.Count)
+}
+
+/*
+[RateLimiter] is a [Limiter] that follows the [broadcasting limits] of Telegram.
+
+Only methods that send messages (send*, forward*, copy*) to a chat are limited,
+any other request is never delayed.
+
+[broadcasting limits]: https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+*/
+type RateLimiter struct {
+	// Limit shared by all chats, by default 30 messages per second.
+	//
+	// Every bot has its own, see [RateLimiter.ForBot].
+	GlobalLimit Limit
+
+	// Limit for each private chat, by default 1 message per second.
+	PrivateLimit Limit
+
+	// Limit for each group, supergroup or channel, by default 20 messages per minute.
+	GroupLimit Limit
+
+	mu        sync.Mutex
+	globals   map[string]*bucket
+	chats     map[chatKey]*bucket
+	nextPrune int
+
+	queued atomic.Int64
+}
+
+func NewRateLimiter() *RateLimiter {
+	limiter := new(RateLimiter)
+	limiter.GlobalLimit = Limit{Count: 30, Period: time.Second}
+	limiter.PrivateLimit = Limit{Count: 1, Period: time.Second}
+	limiter.GroupLimit = Limit{Count: 20, Period: time.Minute}
+
+	return limiter
+}
+
+// QueueDepth returns the number of requests currently waiting in the limiter.
+func (limiter *RateLimiter) QueueDepth() int {
+	return int(limiter.queued.Load())
+}
+
+/*
+[ForBot] returns a [Limiter] with its own limits for the bot with the given id,
+sharing the queue of limiter, see [RateLimiter.QueueDepth].
+
+Telegram enforces the limits for each bot, so the bots sharing a limiter,
+e.g. in a [BotManager], should use ForBot.
+*/
+func (limiter *RateLimiter) ForBot(id int64) Limiter {
+	return &botLimiter{limiter: limiter, scope: strconv.FormatInt(id, 10)}
+}
+
+// botLimiter applies the limits of a [RateLimiter] to a single bot.
+type botLimiter struct {
+	limiter *RateLimiter
+	scope   string
+}
+
+func (limiter *botLimiter) Wait(ctx context.Context, method string, chatID string) error {
+	return limiter.limiter.wait(ctx, limiter.scope, method, chatID)
+}
+
+// chatKey identifies the bucket of a chat for the bot of scope.
+type chatKey struct {
+	scope  string
+	chatID string
+}
+
+func (limiter *RateLimiter) Wait(ctx context.Context, method string, chatID string) error {
+	return limiter.wait(ctx, EmptyString, method, chatID)
+}
+
+func (limiter *RateLimiter) wait(ctx context.Context, scope string, method string, chatID string) error {
+	if chatID == EmptyString || !isSendMethod(method) {
+		return nil
+	}
+
+	limiter.queued.Add(1)
+	defer limiter.queued.Add(-1)
+
+	key := chatKey{scope: scope, chatID: chatID}
+
+	at, reserved := limiter.reserve(key, time.Now())
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// the request is not sent, the next one can take its slot
+		limiter.cancel(key, reserved)
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reservation is the state of the buckets after a reserve, see [RateLimiter.cancel].
+type reservation struct {
+	global time.Time
+	chat   time.Time
+}
+
+// cancel gives back the slots taken by reserve for key, if they were not followed by others.
+func (limiter *RateLimiter) cancel(key chatKey, reserved reservation) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.globals[key.scope].cancel(reserved.global)
+
+	if chat, ok := limiter.chats[key]; ok {
+		chat.cancel(reserved.chat)
+	}
+}
+
+// reserve takes a slot for key and returns when the request can be sent.
+func (limiter *RateLimiter) reserve(key chatKey, now time.Time) (time.Time, reservation) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limiter.globals == nil {
+		limiter.globals = make(map[string]*bucket)
+	}
+
+	global, ok := limiter.globals[key.scope]
+	if !ok {
+		global = &bucket{limit: limiter.GlobalLimit}
+		limiter.globals[key.scope] = global
+	}
+
+	if limiter.chats == nil {
+		limiter.chats = make(map[chatKey]*bucket)
+	}
+
+	if len(limiter.chats) >= limiter.nextPrune {
+		limiter.prune(now)
+	}
+
+	chat, ok := limiter.chats[key]
+	if !ok {
+		chat = &bucket{limit: limiter.PrivateLimit}
+
+		if isGroupChatID(key.chatID) {
+			chat.limit = limiter.GroupLimit
+		}
+
+		limiter.chats[key] = chat
+	}
+
+	globalAt := global.reserve(now)
+	chatAt := chat.reserve(now)
+
+	reserved := reservation{global: global.tat, chat: chat.tat}
+
+	if globalAt.After(chatAt) {
+		return globalAt, reserved
+	}
+
+	return chatAt, reserved
+}
+
+// prune forgets the chats that are not limited anymore.
+func (limiter *RateLimiter) prune(now time.Time) {
+	for key, chat := range limiter.chats {
+		if !chat.tat.After(now) {
+			delete(limiter.chats, key)
+		}
+	}
+
+	limiter.nextPrune = max(1024, 2*len(limiter.chats))
+}
+
+// bucket implements the generic cell rate algorithm,
+// tat is the theoretical arrival time of the next request.
+type bucket struct {
+	limit Limit
+	tat   time.Time
+}
+
+func (b *bucket) reserve(now time.Time) time.Time {
+	if b.limit.Count <= 0 || b.limit.Period <= 0 {
+		return now
+	}
+
+	interval := b.limit.interval()
+
+	tat := b.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	at := tat.Add(-time.Duration(b.limit.Count-1) * interval)
+	if at.Before(now) {
+		at = now
+	}
+
+	b.tat = tat.Add(interval)
+
+	return at
+}
+
+/*
+cancel gives back the last slot, if tat is still the one set by its reservation.
+
+Otherwise a later reservation holds the next slot, and giving one back
+would let two requests share it, the gap is left instead.
+*/
+func (b *bucket) cancel(tat time.Time) {
+	if b.limit.Count <= 0 || b.limit.Period <= 0 || !b.tat.Equal(tat) {
+		return
+	}
+
+	b.tat = b.tat.Add(-b.limit.interval())
+}
+
+func isSendMethod(method string) bool {
+	return strings.HasPrefix(method, "send") ||
+		strings.HasPrefix(method, "forward") ||
+		strings.HasPrefix(method, "copy")
+}
+
+// isGroupChatID reports whether chatID belongs to a group, a supergroup or a channel.
+func isGroupChatID(chatID string) bool {
+	return strings.HasPrefix(chatID, "-") || strings.HasPrefix(chatID, "@")
+}
+
+// chatIDFromJSON returns the chat_id field of a JSON encoded request.
+func chatIDFromJSON(body []byte) string {
+	var params struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+
+	if json.Unmarshal(body, &params) != nil || params.ChatID == nil {
+		return EmptyString
+	}
+
+	var chatID string
+	if json.Unmarshal(params.ChatID, &chatID) == nil {
+		return chatID
+	}
+
+	return string(params.ChatID)
+}
+
+func (bot *Bot) wait(ctx context.Context, request *APIRequest) error {
+	if bot.Config.Limiter == nil {
+		return nil
+	}
+
+	return bot.Config.Limiter.Wait(ctx, request.Method, request.chatID())
+}
@@ -0,0 +1,168 @@
+package aquagram_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+func TestRateLimiterPerChat(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.PrivateLimit = aquagram.Limit{Count: 1, Period: 100 * time.Millisecond}
+
+	ctx := context.Background()
+	start := time.Now()
+
+	for _, chatID := range []string{"1", "2", "1"} {
+		if err := limiter.Wait(ctx, "sendMessage", chatID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("second message to the same chat was not delayed (%s)", elapsed)
+	}
+}
+
+func TestRateLimiterIgnoresOtherMethods(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.PrivateLimit = aquagram.Limit{Count: 1, Period: time.Hour}
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, "getChatMember", "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.GroupLimit = aquagram.Limit{Count: 1, Period: time.Hour}
+
+	limiter.Wait(context.Background(), "sendMessage", "-100")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- limiter.Wait(ctx, "sendMessage", "-100")
+	}()
+
+	for limiter.QueueDepth() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if limiter.QueueDepth() != 0 {
+		t.Errorf("queue depth should be 0, got %d", limiter.QueueDepth())
+	}
+}
+
+func TestRateLimiterCancelReservation(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.GroupLimit = aquagram.Limit{Count: 1, Period: 200 * time.Millisecond}
+
+	start := time.Now()
+	limiter.Wait(context.Background(), "sendMessage", "-100")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- limiter.Wait(ctx, "sendMessage", "-100")
+	}()
+
+	for limiter.QueueDepth() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	// the canceled request gave back its slot
+	if err := limiter.Wait(context.Background(), "sendMessage", "-100"); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 350*time.Millisecond {
+		t.Errorf("the next message was delayed by the canceled one (%s)", elapsed)
+	}
+}
+
+func TestRateLimiterForBot(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.GroupLimit = aquagram.Limit{Count: 1, Period: time.Hour}
+
+	ctx := context.Background()
+
+	// the same chat for two bots
+	for _, id := range []int64{1, 2} {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		if err := limiter.ForBot(id).Wait(ctx, "sendMessage", "-100"); err != nil {
+			t.Errorf("bot %d was limited by the other one: %v", id, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.ForBot(1).Wait(ctx, "sendMessage", "-100"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("the second message of bot 1 was not limited, got %v", err)
+	}
+}
+
+func TestRateLimiterCancelMiddleReservation(t *testing.T) {
+	limiter := aquagram.NewRateLimiter()
+	limiter.GroupLimit = aquagram.Limit{Count: 1, Period: 100 * time.Millisecond}
+
+	start := time.Now()
+	limiter.Wait(context.Background(), "sendMessage", "-100")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	canceled := make(chan error)
+	go func() {
+		canceled <- limiter.Wait(ctx, "sendMessage", "-100")
+	}()
+
+	for limiter.QueueDepth() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// reserved after the canceled one
+	sent := make(chan time.Duration)
+	go func() {
+		limiter.Wait(context.Background(), "sendMessage", "-100")
+		sent <- time.Since(start)
+	}()
+
+	for limiter.QueueDepth() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-canceled
+
+	// the slot of the canceled request is not given back, it would be shared
+	if err := limiter.Wait(context.Background(), "sendMessage", "-100"); err != nil {
+		t.Fatal(err)
+	}
+
+	last := time.Since(start)
+
+	if other := <-sent; last-other < 80*time.Millisecond {
+		t.Errorf("two messages were sent in the same interval, at %s and %s", other, last)
+	}
+}
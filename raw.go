@@ -1,166 +1,161 @@
 package aquagram
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
-	"os"
+	"log/slog"
+	"time"
 )
 
 type TelegramResponse struct {
-	Ok          bool   `json:"ok"`
-	ErrorCode   int    `json:"error_code"`
-	Description string `json:"description"`
+	Ok          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
 }
 
-func (bot *Bot) methodURL(method string) string {
-	return fmt.Sprintf("%s/bot%s/%s", bot.Config.API, bot.token, method)
-}
-
-func (bot *Bot) Raw(ctx context.Context, method string, params any) ([]byte, error) {
-	url := bot.methodURL(method)
-
-	reqCtx, reqCancel := context.WithCancel(ctx)
-
-	go func() {
-		for {
-			select {
-			case <-bot.stopContext.Done():
-				reqCancel()
-			}
-		}
-	}()
-
-	body := new(bytes.Buffer)
-	encoder := json.NewEncoder(body)
-	encoder.Encode(params)
-
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, body)
-	if err != nil {
-		return nil, err
+func (bot *Bot) transport() Transport {
+	if bot.Config.Transport != nil {
+		return bot.Config.Transport
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return NewHTTPTransport(bot.Config.API, bot.token, bot.Config.Client)
+}
 
-	res, err := bot.Config.Client.Do(req)
-	if err != nil {
-		return nil, err
+func (bot *Bot) Raw(ctx context.Context, method string, params any) ([]byte, error) {
+	request := &APIRequest{
+		Method: method,
+		Params: params,
 	}
 
-	return io.ReadAll(res.Body)
+	return bot.Do(ctx, request)
 }
 
 func (bot *Bot) RawFile(ctx context.Context, method string, params Params, files Files) ([]byte, error) {
-	url := bot.methodURL(method)
-
-	reqCtx, reqCancel := context.WithCancel(ctx)
+	request := &APIRequest{
+		Method: method,
+		Params: params,
+		Files:  files,
+	}
 
-	go func() {
-		for {
-			select {
-			case <-bot.stopContext.Done():
-				reqCancel()
-			}
-		}
-	}()
+	return bot.Do(ctx, request)
+}
 
-	pipeReader, pipeWriter := io.Pipe()
-	form := multipart.NewWriter(pipeWriter)
+/*
+[Do] sends a request through the [Transport] of the bot,
+applying its [Limiter] and [RetryPolicy].
 
-	go func() {
-		defer pipeWriter.Close()
-		defer form.Close()
+[Config.OnRequestFuncs] are called before, and [Config.OnResponseFuncs] after.
 
-		for fieldname, value := range params {
-			form.WriteField(fieldname, value)
+The request is canceled when ctx is done or the bot is stopped.
+*/
+func (bot *Bot) Do(ctx context.Context, request *APIRequest) ([]byte, error) {
+	for _, fn := range bot.Config.OnRequestFuncs {
+		if err := fn(bot, request); err != nil {
+			return nil, err
 		}
+	}
 
-		for fieldname, file := range files {
-			fileReader := file.FromReader
-
-			if file.FromReader == nil && file.FromPath != EmptyString {
-				f, err := os.Open(file.FromPath)
-				if err != nil {
-					pipeWriter.CloseWithError(err)
-				}
+	if err := bot.wait(ctx, request); err != nil {
+		return nil, err
+	}
 
-				fileReader = f
-				defer f.Close()
+	return bot.exchange(ctx, request)
+}
 
-				if file.FileName == EmptyString {
-					file.FileName = f.Name()
-				}
-			}
+// exchange sends a request whose hooks and limiter were already applied.
+func (bot *Bot) exchange(ctx context.Context, request *APIRequest) ([]byte, error) {
+	start := time.Now()
 
-			if fileReader != nil {
-				writeFile(form, fieldname, fileReader, file.FileName)
-				continue
-			}
+	data, err := bot.send(ctx, request)
 
-			var str string
+	debug := bot.Config.Logger.Enabled(ctx, slog.LevelDebug)
 
-			if file.FromFileID != EmptyString {
-				str = file.FromFileID
+	if debug || len(bot.Config.OnResponseFuncs) > 0 || bot.Config.Metrics != nil {
+		response := &APIResponse{
+			Request:  request,
+			Data:     data,
+			Err:      err,
+			Duration: time.Since(start),
+		}
 
-			} else if file.FromURL != EmptyString {
-				str = file.FromURL
-			}
+		if err == nil {
+			response.Err = decodeError(data)
+		}
 
-			if str != EmptyString {
-				form.WriteField(fieldname, str)
-				continue
-			}
+		for _, fn := range bot.Config.OnResponseFuncs {
+			fn(bot, response)
+		}
 
-			pipeWriter.CloseWithError(ErrUnknownFileSource)
+		if bot.Config.Metrics != nil {
+			bot.Config.Metrics.APICall(request.Method, outcome(response.Err), response.Duration)
 		}
-	}()
 
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, pipeReader)
-	if err != nil {
-		return nil, err
+		if debug {
+			bot.logResponse(ctx, response)
+		}
 	}
 
-	req.Header.Set("Content-Type", form.FormDataContentType())
+	return data, err
+}
 
-	res, err := bot.Config.Client.Do(req)
-	if err != nil {
-		return nil, err
+func (bot *Bot) logResponse(ctx context.Context, response *APIResponse) {
+	attrs := []slog.Attr{
+		slog.String("method", response.Request.Method),
+		slog.Duration("duration", response.Duration),
 	}
 
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	if chatID := response.Request.chatID(); chatID != EmptyString {
+		attrs = append(attrs, slog.String("chat_id", chatID))
+	}
+
+	if response.Err != nil {
+		attrs = append(attrs, slog.Any("error", response.Err))
 	}
 
-	return data, nil
+	bot.Config.Logger.LogAttrs(ctx, slog.LevelDebug, "api request", attrs...)
 }
 
-func stringFromFile(file *InputFile) string {
-	if file.FromFileID != EmptyString {
-		return file.FromFileID
-	}
+func (bot *Bot) send(ctx context.Context, request *APIRequest) ([]byte, error) {
+	if bot.Config.RetryPolicy != nil && request.Files != nil {
+		files, err := rewindableFiles(request.Files)
+		if err != nil {
+			return nil, err
+		}
 
-	if file.FromURL != EmptyString {
-		return file.FromURL
+		copied := *request
+		copied.Files = files
+		request = &copied
 	}
 
-	return EmptyString
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(bot.stopContext, cancel)
+	defer stop()
+
+	transport := bot.transport()
+
+	return bot.retry(ctx, request.Method, func() ([]byte, error) {
+		if err := rewindFiles(request.Files); err != nil {
+			return nil, err
+		}
+
+		return transport.Do(ctx, request)
+	})
 }
 
-func writeFile(writer *multipart.Writer, field string, file io.Reader, fileName string) error {
-	part, err := writer.CreateFormFile(field, fileName)
-	if err != nil {
+// decodeError returns the [*APIError] of an unsuccessful response.
+func decodeError(data []byte) error {
+	var res TelegramResponse
+
+	if err := json.Unmarshal(data, &res); err != nil {
 		return err
 	}
 
-	_, err = io.Copy(part, file)
-	return err
-}
+	if res.Ok {
+		return nil
+	}
 
-func errTgBadRequest(code int, description string) error {
-	return fmt.Errorf("%w: (%d): %s", ErrTgBadRequest, code, description)
+	return newAPIError(res.ErrorCode, res.Description, res.Parameters)
 }
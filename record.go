@@ -0,0 +1,168 @@
+package aquagram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedUpdate is a line written by [UpdateRecorder].
+type RecordedUpdate struct {
+	Time   time.Time       `json:"time"`
+	Update json.RawMessage `json:"update"`
+}
+
+/*
+[UpdateRecorder] writes the received updates as JSON lines,
+to be replayed later with [ReplayUpdater]:
+
+	file, err := os.Create("updates.jsonl")
+	...
+	recorder := aquagram.NewUpdateRecorder(file)
+	updater := aquagram.RecordUpdates(aquagram.NewPollingUpdater(bot), recorder)
+
+	bot.StartWithUpdater(updater)
+
+The updater must be run by the bot, see [RecordUpdates].
+*/
+type UpdateRecorder struct {
+	mu     sync.Mutex
+	writer io.Writer
+	err    error
+}
+
+func NewUpdateRecorder(writer io.Writer) *UpdateRecorder {
+	recorder := new(UpdateRecorder)
+	recorder.writer = writer
+
+	return recorder
+}
+
+// Record writes update as received from Telegram, with the current time.
+func (recorder *UpdateRecorder) Record(update *Update) error {
+	data := update.Raw()
+
+	if data == nil {
+		var err error
+
+		data, err = json.Marshal(update)
+		if err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(RecordedUpdate{Time: time.Now(), Update: data})
+	if err != nil {
+		return err
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	_, err = recorder.writer.Write(append(line, '\n'))
+	if err != nil && recorder.err == nil {
+		recorder.err = err
+	}
+
+	return err
+}
+
+// Err returns the first error occurred writing an update.
+func (recorder *UpdateRecorder) Err() error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return recorder.err
+}
+
+type recordingUpdater struct {
+	updater  Updater
+	recorder *UpdateRecorder
+}
+
+/*
+[RecordUpdates] returns an [Updater] that records with recorder the updates of updater.
+
+Only the updates passed by Run are recorded: a [WebhookUpdater] mounted
+as an [http.Handler] without Run passes its updates to the bot directly,
+so they are not recorded.
+*/
+func RecordUpdates(updater Updater, recorder *UpdateRecorder) Updater {
+	return &recordingUpdater{
+		updater:  updater,
+		recorder: recorder,
+	}
+}
+
+func (updater *recordingUpdater) Run(ctx context.Context, sink UpdateSink) error {
+	return updater.updater.Run(ctx, func(update *Update, done func()) error {
+		// the errors are kept by the recorder, see Err
+		updater.recorder.Record(update)
+
+		return sink(update, done)
+	})
+}
+
+/*
+[ReplayUpdater] passes again the updates written by an [UpdateRecorder],
+e.g. to reproduce an issue against the aquagramtest fake server.
+*/
+type ReplayUpdater struct {
+	Reader io.Reader
+
+	// Speed of the replay compared to the recording,
+	// 2 waits half the time between the updates.
+	//
+	// By default is 1, with 0 the updates are passed without waiting.
+	Speed float64
+}
+
+func NewReplayUpdater(reader io.Reader) *ReplayUpdater {
+	updater := new(ReplayUpdater)
+	updater.Reader = reader
+	updater.Speed = 1
+
+	return updater
+}
+
+// Run returns once all the recorded updates were passed to sink.
+func (updater *ReplayUpdater) Run(ctx context.Context, sink UpdateSink) error {
+	decoder := json.NewDecoder(updater.Reader)
+
+	var last time.Time
+
+	for {
+		var record struct {
+			Time   time.Time `json:"time"`
+			Update *Update   `json:"update"`
+		}
+
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if updater.Speed > 0 && !last.IsZero() {
+			delay := time.Duration(float64(record.Time.Sub(last)) / updater.Speed)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		last = record.Time
+
+		if record.Update != nil {
+			sink(record.Update, nil)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package aquagram_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	raw := `{"update_id":7,"message":{"message_id":1,"date":0,"chat":{"id":42,"type":"private"},"text":"hello"},"unknown_field":true}`
+
+	update := new(aquagram.Update)
+	if err := json.Unmarshal([]byte(raw), update); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := make(chan *aquagram.Update, 1)
+	updates <- update
+	close(updates)
+
+	buf := new(bytes.Buffer)
+	recorder := aquagram.NewUpdateRecorder(buf)
+
+	updater := aquagram.RecordUpdates(aquagram.NewChannelUpdater(updates), recorder)
+	updater.Run(context.Background(), func(update *aquagram.Update, done func()) error {
+		return nil
+	})
+
+	if recorder.Err() != nil {
+		t.Fatal(recorder.Err())
+	}
+
+	if !strings.Contains(buf.String(), `"unknown_field":true`) {
+		t.Fatalf("the raw update was not recorded: %s", buf.String())
+	}
+
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	var mu sync.Mutex
+	var texts []string
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		texts = append(texts, message.Text)
+		return nil
+	})
+
+	replay := aquagram.NewReplayUpdater(buf)
+	replay.Speed = 0
+
+	if err := bot.StartWithUpdater(replay); err != nil {
+		t.Fatal(err)
+	}
+
+	bot.Shutdown(context.Background())
+
+	if len(texts) != 1 || texts[0] != "hello" {
+		t.Errorf("unexpected replayed messages %v", texts)
+	}
+}
@@ -0,0 +1,235 @@
+package aquagram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+/*
+[RetryPolicy] describes how failed API requests are repeated.
+
+Requests are retried when Telegram answers with a flood control error (429),
+honoring its retry_after parameter, and with an exponential backoff when the
+server fails (5xx) or a network error occurs.
+
+A request that sends a message (send*, forward*, copy*) may reach Telegram
+even if its response is lost, so after a network error it is only retried
+when it was never sent, e.g. the connection was refused. Otherwise the
+message could be sent twice.
+*/
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// Delay before the first retry, doubled on each attempt.
+	MinBackoff time.Duration
+
+	// Upper bound for the exponential backoff.
+	MaxBackoff time.Duration
+
+	// Longest retry_after that will be waited, longer
+	// waits return the error immediately.
+	//
+	// Zero means no limit.
+	MaxRetryAfter time.Duration
+
+	// Sleep waits delay before a retry, or returns an error if ctx is done before.
+	//
+	// By default it uses a timer, it can be replaced e.g. to avoid waiting in tests.
+	Sleep func(ctx context.Context, delay time.Duration) error
+}
+
+func DefaultRetryPolicy() *RetryPolicy {
+	policy := new(RetryPolicy)
+	policy.MaxAttempts = 3
+	policy.MinBackoff = 500 * time.Millisecond
+	policy.MaxBackoff = 30 * time.Second
+
+	return policy
+}
+
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.MinBackoff
+
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+
+		if policy.MaxBackoff > 0 && delay >= policy.MaxBackoff {
+			return policy.MaxBackoff
+		}
+	}
+
+	return delay
+}
+
+// delay reports whether a request for method should be repeated and how long to wait before.
+func (policy *RetryPolicy) delay(method string, attempt int, data []byte, err error) (time.Duration, bool) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.ErrorCode < 500 {
+				return 0, false
+			}
+
+		} else if isSendMethod(method) && !notSent(err) {
+			// the message may have been sent, only its response was lost
+			return 0, false
+		}
+
+		return policy.backoff(attempt), true
+	}
+
+	var res TelegramResponse
+	if json.Unmarshal(data, &res) != nil || res.Ok {
+		return 0, false
+	}
+
+	if res.ErrorCode == 429 {
+		if res.Parameters == nil || res.Parameters.RetryAfter == 0 {
+			return policy.backoff(attempt), true
+		}
+
+		retryAfter := time.Duration(res.Parameters.RetryAfter) * time.Second
+
+		if policy.MaxRetryAfter > 0 && retryAfter > policy.MaxRetryAfter {
+			return 0, false
+		}
+
+		return retryAfter, true
+	}
+
+	if res.ErrorCode >= 500 {
+		return policy.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// notSent reports whether err proves the request was never sent, e.g. it could not connect.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// retry executes do, a request for method, until it succeeds or the retry policy of the bot gives up.
+func (bot *Bot) retry(ctx context.Context, method string, do func() ([]byte, error)) ([]byte, error) {
+	policy := bot.Config.RetryPolicy
+
+	for attempt := 1; ; attempt++ {
+		data, err := do()
+
+		if policy == nil || attempt >= policy.MaxAttempts {
+			return data, err
+		}
+
+		wait, ok := policy.delay(method, attempt, data, err)
+		if !ok {
+			return data, err
+		}
+
+		if policy.Sleep != nil {
+			if err := policy.Sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+
+		case <-bot.stopContext.Done():
+			timer.Stop()
+			return nil, bot.stopContext.Err()
+
+		case <-timer.C:
+		}
+	}
+}
+
+// rewindReader remembers where a reader started,
+// so a multipart body can be built again on retries.
+type rewindReader struct {
+	io.ReadSeeker
+	start int64
+}
+
+func (r *rewindReader) rewind() error {
+	_, err := r.Seek(r.start, io.SeekStart)
+	return err
+}
+
+// rewindableFiles returns a copy of files whose readers can be read more than once.
+//
+// Readers that are not an [io.Seeker] are buffered in memory.
+func rewindableFiles(files Files) (Files, error) {
+	out := make(Files, len(files))
+
+	for fieldname, file := range files {
+		if file == nil || file.FromReader == nil {
+			out[fieldname] = file
+			continue
+		}
+
+		reader := new(rewindReader)
+
+		if seeker, ok := file.FromReader.(io.ReadSeeker); ok {
+			start, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+
+			reader.ReadSeeker = seeker
+			reader.start = start
+
+		} else {
+			data, err := io.ReadAll(file.FromReader)
+			if err != nil {
+				return nil, err
+			}
+
+			reader.ReadSeeker = bytes.NewReader(data)
+		}
+
+		copied := *file
+		copied.FromReader = reader
+
+		out[fieldname] = &copied
+	}
+
+	return out, nil
+}
+
+func rewindFiles(files Files) error {
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+
+		if reader, ok := file.FromReader.(*rewindReader); ok {
+			if err := reader.rewind(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package aquagram_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+)
+
+func newRetryBot(url string) *aquagram.Bot {
+	bot := aquagram.NewBot("token")
+	bot.Config.API = url
+	bot.Config.RetryPolicy = &aquagram.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+
+	return bot
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`)
+			return
+		}
+
+		io.WriteString(w, `{"ok":true,"result":true}`)
+	}))
+	defer server.Close()
+
+	bot := newRetryBot(server.URL)
+
+	var delays []time.Duration
+
+	bot.Config.RetryPolicy.Sleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+
+	if err := bot.LogOut(); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("retry_after was not honored, waited %v", delays)
+	}
+}
+
+func TestRetryServerError(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+		io.WriteString(w, "<html>bad gateway</html>")
+	}))
+	defer server.Close()
+
+	bot := newRetryBot(server.URL)
+
+	err := bot.LogOut()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestRetryMultipart(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("document")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		data, _ := io.ReadAll(file)
+		if string(data) != "file content" {
+			t.Errorf("unexpected file content %q", data)
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`)
+			return
+		}
+
+		io.WriteString(w, `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`)
+	}))
+	defer server.Close()
+
+	bot := newRetryBot(server.URL)
+
+	// strings.Reader is wrapped so it can not be seeked
+	document := aquagram.InputFileFromReader(io.MultiReader(strings.NewReader("file content")))
+	document.FileName = "sample.txt"
+
+	if _, err := bot.SendDocument("1", document, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+func TestRetryNetworkErrorSendMethod(t *testing.T) {
+	var calls atomic.Int32
+
+	// the request is received, but the connection is closed before the response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	bot := newRetryBot(server.URL)
+	bot.Config.RetryPolicy.Sleep = func(ctx context.Context, delay time.Duration) error {
+		return nil
+	}
+
+	if _, err := bot.SendMessage("42", "hello", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// the message may have been sent
+	if calls.Load() != 1 {
+		t.Errorf("sendMessage was retried, %d calls", calls.Load())
+	}
+
+	calls.Store(0)
+
+	if err := bot.LogOut(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls of logOut, got %d", calls.Load())
+	}
+}
+
+func TestRetryConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	bot := newRetryBot(server.URL)
+
+	var retries int
+
+	bot.Config.RetryPolicy.Sleep = func(ctx context.Context, delay time.Duration) error {
+		retries++
+		return nil
+	}
+
+	if _, err := bot.SendMessage("42", "hello", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// never sent, so it can be retried
+	if retries != 2 {
+		t.Errorf("expected 2 retries, got %d", retries)
+	}
+}
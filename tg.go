@@ -134,3 +134,13 @@ type PaidMediaInfo struct{}
 // TODO implement all fields
 // https://core.telegram.org/bots/api#sticker
 type Sticker struct{}
+
+// https://core.telegram.org/bots/api#location
+type Location struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
@@ -0,0 +1,342 @@
+package aquagram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+)
+
+// APIRequest is a single call to a Bot API method.
+type APIRequest struct {
+	Method string
+
+	// Parameters of the method, encoded as JSON when there are no files.
+	// Otherwise it must be [Params].
+	Params any
+
+	// Files to upload within a multipart request.
+	Files Files
+
+	// Params was copied by Set, so it can be changed
+	ownParams bool
+}
+
+// Get returns a parameter of the request, JSON encoded unless it is a string.
+func (request *APIRequest) Get(key string) string {
+	switch params := request.Params.(type) {
+	case nil:
+		return EmptyString
+	case Params:
+		return params[key]
+	case map[string]string:
+		return params[key]
+	}
+
+	params, err := request.paramsMap()
+	if err != nil {
+		return EmptyString
+	}
+
+	value, ok := params[key]
+	if !ok || value == nil {
+		return EmptyString
+	}
+
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+/*
+[Set] sets a parameter of the request, e.g. to force protect_content:
+
+	request.Set("protect_content", true)
+
+Parameters given as a struct are converted to a map first, and maps
+are copied on the first call, so the parameters of the caller are not changed.
+*/
+func (request *APIRequest) Set(key string, value any) error {
+	switch params := request.Params.(type) {
+	case Params:
+		return setStringParam(ownParams(request, params), key, value)
+	case map[string]string:
+		return setStringParam(ownParams(request, params), key, value)
+	case map[string]any:
+		ownParams(request, params)[key] = value
+		return nil
+	}
+
+	if request.Files != nil {
+		params := make(Params)
+		request.Params = params
+		request.ownParams = true
+
+		return setStringParam(params, key, value)
+	}
+
+	params, err := request.paramsMap()
+	if err != nil {
+		return err
+	}
+
+	params[key] = value
+	request.Params = params
+	request.ownParams = true
+
+	return nil
+}
+
+// ownParams returns the parameters of request, copying them if they belong to the caller.
+func ownParams[M ~map[string]V, V any](request *APIRequest, params M) M {
+	if request.ownParams {
+		return params
+	}
+
+	copied := make(M, len(params)+1)
+	maps.Copy(copied, params)
+
+	request.Params = copied
+	request.ownParams = true
+
+	return copied
+}
+
+// paramsMap decodes the parameters of the request as a JSON object.
+func (request *APIRequest) paramsMap() (map[string]any, error) {
+	params := make(map[string]any)
+
+	if request.Params == nil {
+		return params, nil
+	}
+
+	data, err := json.Marshal(request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		params = make(map[string]any)
+	}
+
+	return params, nil
+}
+
+func setStringParam(params map[string]string, key string, value any) error {
+	if str, ok := value.(string); ok {
+		params[key] = str
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	params[key] = string(data)
+	return nil
+}
+
+func (request *APIRequest) chatID() string {
+	return request.Get("chat_id")
+}
+
+// APIResponse is the outcome of an [APIRequest].
+type APIResponse struct {
+	Request *APIRequest
+
+	// Raw response of the Bot API, nil if the request failed before.
+	Data []byte
+
+	// Error returned by the transport, or the [*APIError] answered by Telegram.
+	Err error
+
+	// Time spent sending the request, including retries.
+	Duration time.Duration
+}
+
+/*
+[Transport] sends API requests and returns the raw response of the Bot API.
+
+By default the bot uses an [HTTPTransport] built from its [Config],
+use [Config.Transport] to record requests, fake responses in tests
+or to send them through a queue.
+*/
+type Transport interface {
+	Do(ctx context.Context, request *APIRequest) ([]byte, error)
+}
+
+// HTTPTransport sends requests to the Bot API over HTTP.
+type HTTPTransport struct {
+	// API URL, e.g. https://api.telegram.org
+	API    string
+	Token  string
+	Client *http.Client
+}
+
+func NewHTTPTransport(api string, token string, client *http.Client) *HTTPTransport {
+	transport := new(HTTPTransport)
+	transport.API = api
+	transport.Token = token
+	transport.Client = client
+
+	return transport
+}
+
+func (transport *HTTPTransport) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", transport.API, transport.Token, method)
+}
+
+func (transport *HTTPTransport) Do(ctx context.Context, request *APIRequest) ([]byte, error) {
+	if request.Files == nil {
+		body, err := json.Marshal(request.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		return transport.post(ctx, request.Method, bytes.NewReader(body), "application/json")
+	}
+
+	params, ok := request.Params.(Params)
+	if !ok && request.Params != nil {
+		return nil, fmt.Errorf("%w: multipart params must be of type Params, got %T", ErrUserError, request.Params)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+
+	form := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		err := writeMultipart(form, params, request.Files)
+		if err == nil {
+			err = form.Close()
+		}
+
+		pipeWriter.CloseWithError(err)
+	}()
+
+	return transport.post(ctx, request.Method, pipeReader, form.FormDataContentType())
+}
+
+func (transport *HTTPTransport) post(ctx context.Context, method string, body io.Reader, contentType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, transport.methodURL(method), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	client := transport.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// e.g. a 502 from a proxy in front of the Bot API
+	if res.StatusCode >= 500 && !json.Valid(data) {
+		return nil, newAPIError(res.StatusCode, res.Status, nil)
+	}
+
+	return data, nil
+}
+
+func writeMultipart(form *multipart.Writer, params Params, files Files) error {
+	for fieldname, value := range params {
+		if err := form.WriteField(fieldname, value); err != nil {
+			return err
+		}
+	}
+
+	for fieldname, file := range files {
+		if file == nil {
+			return ErrUnknownFileSource
+		}
+
+		fileReader := file.FromReader
+		fileName := file.FileName
+
+		if fileReader == nil && file.FromPath != EmptyString {
+			f, err := os.Open(file.FromPath)
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			fileReader = f
+
+			if fileName == EmptyString {
+				fileName = f.Name()
+			}
+		}
+
+		if fileReader != nil {
+			if err := writeFile(form, fieldname, fileReader, fileName); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		str := stringFromFile(file)
+		if str == EmptyString {
+			return ErrUnknownFileSource
+		}
+
+		if err := form.WriteField(fieldname, str); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stringFromFile(file *InputFile) string {
+	if file.FromFileID != EmptyString {
+		return file.FromFileID
+	}
+
+	if file.FromURL != EmptyString {
+		return file.FromURL
+	}
+
+	return EmptyString
+}
+
+func writeFile(writer *multipart.Writer, field string, file io.Reader, fileName string) error {
+	part, err := writer.CreateFormFile(field, fileName)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, file)
+	return err
+}
@@ -0,0 +1,131 @@
+package aquagram_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+type recordingTransport struct {
+	requests []*aquagram.APIRequest
+}
+
+func (transport *recordingTransport) Do(ctx context.Context, request *aquagram.APIRequest) ([]byte, error) {
+	transport.requests = append(transport.requests, request)
+	return []byte(`{"ok":true,"result":{"id":1,"first_name":"aquagram"}}`), nil
+}
+
+func TestTransport(t *testing.T) {
+	transport := new(recordingTransport)
+
+	bot := aquagram.NewBot("token")
+	bot.Config.Transport = transport
+
+	me, err := bot.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if me.FirstName != "aquagram" {
+		t.Errorf("unexpected user %+v", me)
+	}
+
+	if len(transport.requests) != 1 || transport.requests[0].Method != "getMe" {
+		t.Errorf("unexpected requests %+v", transport.requests)
+	}
+}
+
+func TestRequestHooks(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	server.AddChat(&aquagram.Chat{ID: 42, Type: aquagram.ChatTypePrivate})
+
+	var responses []*aquagram.APIResponse
+
+	bot := server.NewBot()
+	bot.Config.OnRequestFuncs = append(bot.Config.OnRequestFuncs, func(bot *aquagram.Bot, request *aquagram.APIRequest) error {
+		if strings.HasPrefix(request.Method, "send") {
+			return request.Set("protect_content", true)
+		}
+
+		return nil
+	})
+
+	bot.Config.OnResponseFuncs = append(bot.Config.OnResponseFuncs, func(bot *aquagram.Bot, response *aquagram.APIResponse) {
+		responses = append(responses, response)
+	})
+
+	message, err := bot.SendMessage("42", "hello", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !message.HasProtectedContent || !server.LastRequest("sendMessage").Bool("protect_content") {
+		t.Error("protect_content was not set")
+	}
+
+	bot.SendMessage("-100", "hello", nil)
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+
+	if responses[0].Err != nil || responses[0].Request.Get("text") != "hello" {
+		t.Errorf("unexpected response %+v", responses[0])
+	}
+
+	if !errors.Is(responses[1].Err, aquagram.ErrTgChatNotFound) {
+		t.Errorf("expected ErrTgChatNotFound, got %v", responses[1].Err)
+	}
+}
+
+func TestRequestSetNilParams(t *testing.T) {
+	var params *aquagram.SendMessageParams
+
+	requests := []*aquagram.APIRequest{
+		{Method: "sendMessage"},
+		{Method: "sendMessage", Params: params},
+		{Method: "sendMessage", Params: aquagram.Params(nil)},
+		{Method: "sendMessage", Params: map[string]string(nil)},
+		{Method: "sendMessage", Params: map[string]any(nil)},
+	}
+
+	for _, request := range requests {
+		if err := request.Set("chat_id", "42"); err != nil {
+			t.Fatal(err)
+		}
+
+		if value := request.Get("chat_id"); value != "42" {
+			t.Errorf("chat_id not set on %T, got %q", request.Params, value)
+		}
+	}
+}
+
+func TestRequestSetCopiesParams(t *testing.T) {
+	params := aquagram.Params{"chat_id": "42"}
+
+	for i := 0; i < 2; i++ {
+		request := &aquagram.APIRequest{Method: "sendMessage", Params: params}
+
+		if err := request.Set("protect_content", true); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := request.Set("text", "hello"); err != nil {
+			t.Fatal(err)
+		}
+
+		if request.Get("protect_content") != "true" || request.Get("text") != "hello" || request.Get("chat_id") != "42" {
+			t.Errorf("unexpected params %v", request.Params)
+		}
+	}
+
+	if len(params) != 1 {
+		t.Errorf("the params of the caller were changed: %v", params)
+	}
+}
@@ -1,6 +1,13 @@
 package aquagram
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
 
 type UpdateType string
 
@@ -36,86 +43,318 @@ const (
 	OnPhoto     UpdateType = "photo"
 	OnVideo     UpdateType = "video"
 	OnVoice     UpdateType = "voice"
+
+	OnSuccessfulPayment UpdateType = "successful_payment"
 )
 
 type Update struct {
-	UpdateID              int            `json:"update_id"`
-	Message               *Message       `json:"message,omitempty"`
-	EditedMessage         *Message       `json:"edited_message,omitempty"`
-	ChannelPost           *Message       `json:"channel_post,omitempty"`
-	EditedChannelPost     *Message       `json:"edited_channel_post,omitempty"`
-	BusinessMessage       *Message       `json:"business_message,omitempty"`
-	EditedBusinessMessage *Message       `json:"edited_business_message,omitempty"`
-	CallbackQuery         *CallbackQuery `json:"callback_query,omitempty"`
+	UpdateID              int                 `json:"update_id"`
+	Message               *Message            `json:"message,omitempty"`
+	EditedMessage         *Message            `json:"edited_message,omitempty"`
+	ChannelPost           *Message            `json:"channel_post,omitempty"`
+	EditedChannelPost     *Message            `json:"edited_channel_post,omitempty"`
+	BusinessMessage       *Message            `json:"business_message,omitempty"`
+	EditedBusinessMessage *Message            `json:"edited_business_message,omitempty"`
+	InlineQuery           *InlineQuery        `json:"inline_query,omitempty"`
+	ChosenInlineResult    *ChosenInlineResult `json:"chosen_inline_result,omitempty"`
+	CallbackQuery         *CallbackQuery      `json:"callback_query,omitempty"`
+	ShippingQuery         *ShippingQuery      `json:"shipping_query,omitempty"`
+	PreCheckoutQuery      *PreCheckoutQuery   `json:"pre_checkout_query,omitempty"`
+	Poll                  *Poll               `json:"poll,omitempty"`
+	PollAnswer            *PollAnswer         `json:"poll_answer,omitempty"`
+	MyChatMember          *ChatMemberUpdated  `json:"my_chat_member,omitempty"`
+	ChatMember            *ChatMemberUpdated  `json:"chat_member,omitempty"`
+	ChatJoinRequest       *ChatJoinRequest    `json:"chat_join_request,omitempty"`
+
+	raw json.RawMessage
 }
 
+func (update *Update) UnmarshalJSON(data []byte) error {
+	type plain Update
+
+	if err := json.Unmarshal(data, (*plain)(update)); err != nil {
+		return err
+	}
+
+	update.raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+// Raw returns the JSON update was decoded from, or nil if it was not decoded.
+func (update *Update) Raw() json.RawMessage {
+	return update.raw
+}
+
+// Type returns the type of the event contained in update.
+func (update *Update) Type() UpdateType {
+	switch {
+	case update.Message != nil:
+		return OnMessage
+	case update.EditedMessage != nil:
+		return OnEditedMessage
+	case update.ChannelPost != nil:
+		return OnChannelPost
+	case update.EditedChannelPost != nil:
+		return OnEditedChannelPost
+	case update.BusinessMessage != nil:
+		return OnBusinessMessage
+	case update.EditedBusinessMessage != nil:
+		return OnEditedBusinessMessage
+	case update.InlineQuery != nil:
+		return OnInlineQuery
+	case update.ChosenInlineResult != nil:
+		return OnChosenInlineResult
+	case update.CallbackQuery != nil:
+		return OnCallbackQuery
+	case update.ShippingQuery != nil:
+		return OnShippingQuery
+	case update.PreCheckoutQuery != nil:
+		return OnPreCheckoutQuery
+	case update.Poll != nil:
+		return OnPoll
+	case update.PollAnswer != nil:
+		return OnPollAnswer
+	case update.MyChatMember != nil:
+		return OnMyChatMember
+	case update.ChatMember != nil:
+		return OnChatMember
+	case update.ChatJoinRequest != nil:
+		return OnChatJoinRequest
+	}
+
+	return UpdateType(EmptyString)
+}
+
+// event returns the event contained in update, or nil if unknown.
+func (update *Update) event() Event {
+	switch {
+	case update.Message != nil:
+		return update.Message
+	case update.EditedMessage != nil:
+		return update.EditedMessage
+	case update.ChannelPost != nil:
+		return update.ChannelPost
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost
+	case update.BusinessMessage != nil:
+		return update.BusinessMessage
+	case update.EditedBusinessMessage != nil:
+		return update.EditedBusinessMessage
+	case update.InlineQuery != nil:
+		return update.InlineQuery
+	case update.ChosenInlineResult != nil:
+		return update.ChosenInlineResult
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery
+	case update.ShippingQuery != nil:
+		return update.ShippingQuery
+	case update.PreCheckoutQuery != nil:
+		return update.PreCheckoutQuery
+	case update.Poll != nil:
+		return update.Poll
+	case update.PollAnswer != nil:
+		return update.PollAnswer
+	case update.MyChatMember != nil:
+		return update.MyChatMember
+	case update.ChatMember != nil:
+		return update.ChatMember
+	case update.ChatJoinRequest != nil:
+		return update.ChatJoinRequest
+	}
+
+	return nil
+}
+
+/*
+[DispatchUpdate] runs the middlewares and handlers registered
+for every event contained in update.
+
+Deprecated: use [Bot.ProcessUpdate], which returns the errors of the handlers.
+*/
 func (bot *Bot) DispatchUpdate(update *Update) {
+	bot.ProcessUpdate(update)
+}
+
+/*
+[ProcessUpdate] runs the middlewares and handlers registered
+for every event contained in update.
+
+It returns once all the handlers finished, with the errors they returned.
+*/
+func (bot *Bot) ProcessUpdate(update *Update) error {
+	var errs []error
+
+	handle := func(updateType UpdateType, event Event) {
+		start := time.Now()
+
+		err := bot.HandleEvent(updateType, event)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if bot.Config.Metrics != nil {
+			bot.Config.Metrics.UpdateHandled(updateType, time.Since(start), err)
+		}
+
+		if bot.Config.Logger.Enabled(context.Background(), slog.LevelDebug) {
+			attrs := []any{
+				slog.Int("update_id", update.UpdateID),
+				slog.String("update_type", string(updateType)),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			attrs = append(attrs, eventAttrs(event)...)
+
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+
+			bot.Config.Logger.Debug("update handled", attrs...)
+		}
+	}
+
 	if update.Message != nil {
 		message := update.Message
 		message.process(bot)
 
-		bot.HandleUpdate(OnMessage, message)
+		handle(OnMessage, message)
 
 		if message.Animation != nil {
-			bot.HandleUpdate(OnAnimation, message)
+			handle(OnAnimation, message)
 		}
 
 		if message.Audio != nil {
-			bot.HandleUpdate(OnAudio, message)
+			handle(OnAudio, message)
 		}
 
 		if message.Document != nil {
-			bot.HandleUpdate(OnDocument, message)
+			handle(OnDocument, message)
 		}
 
 		if message.Photo != nil {
-			bot.HandleUpdate(OnPhoto, message)
+			handle(OnPhoto, message)
 		}
 
 		if message.Video != nil {
-			bot.HandleUpdate(OnVideo, message)
+			handle(OnVideo, message)
 		}
 
 		if message.Voice != nil {
-			bot.HandleUpdate(OnVoice, message)
+			handle(OnVoice, message)
+		}
+
+		if message.SuccessfulPayment != nil {
+			handle(OnSuccessfulPayment, message)
 		}
 	}
 
 	if update.EditedMessage != nil {
-		bot.HandleUpdate(OnEditedMessage, update.EditedMessage)
+		update.EditedMessage.process(bot)
+		handle(OnEditedMessage, update.EditedMessage)
 	}
 
 	if update.ChannelPost != nil {
-		bot.HandleUpdate(OnChannelPost, update.ChannelPost)
+		update.ChannelPost.process(bot)
+		handle(OnChannelPost, update.ChannelPost)
 	}
 
 	if update.EditedChannelPost != nil {
-		bot.HandleUpdate(OnEditedChannelPost, update.ChannelPost)
+		update.EditedChannelPost.process(bot)
+		handle(OnEditedChannelPost, update.EditedChannelPost)
 	}
 
 	if update.BusinessMessage != nil {
-		bot.HandleUpdate(OnBusinessMessage, update.BusinessMessage)
+		update.BusinessMessage.process(bot)
+		handle(OnBusinessMessage, update.BusinessMessage)
 	}
 
 	if update.EditedBusinessMessage != nil {
-		bot.HandleUpdate(OnBusinessMessage, update.EditedBusinessMessage)
+		update.EditedBusinessMessage.process(bot)
+		handle(OnEditedBusinessMessage, update.EditedBusinessMessage)
+	}
+
+	if update.InlineQuery != nil {
+		update.InlineQuery.process(bot)
+		handle(OnInlineQuery, update.InlineQuery)
+	}
+
+	if update.ChosenInlineResult != nil {
+		update.ChosenInlineResult.process(bot)
+		handle(OnChosenInlineResult, update.ChosenInlineResult)
 	}
 
 	if update.CallbackQuery != nil {
 		update.CallbackQuery.process(bot)
-		bot.HandleUpdate(OnCallbackQuery, update.CallbackQuery)
+		handle(OnCallbackQuery, update.CallbackQuery)
+	}
+
+	if update.ShippingQuery != nil {
+		update.ShippingQuery.process(bot)
+		handle(OnShippingQuery, update.ShippingQuery)
+	}
+
+	if update.PreCheckoutQuery != nil {
+		update.PreCheckoutQuery.process(bot)
+		handle(OnPreCheckoutQuery, update.PreCheckoutQuery)
+	}
+
+	if update.Poll != nil {
+		update.Poll.process(bot)
+		handle(OnPoll, update.Poll)
 	}
+
+	if update.PollAnswer != nil {
+		update.PollAnswer.process(bot)
+		handle(OnPollAnswer, update.PollAnswer)
+	}
+
+	if update.MyChatMember != nil {
+		update.MyChatMember.my = true
+		update.MyChatMember.process(bot)
+		handle(OnMyChatMember, update.MyChatMember)
+	}
+
+	if update.ChatMember != nil {
+		update.ChatMember.process(bot)
+		handle(OnChatMember, update.ChatMember)
+	}
+
+	if update.ChatJoinRequest != nil {
+		update.ChatJoinRequest.process(bot)
+		handle(OnChatJoinRequest, update.ChatJoinRequest)
+	}
+
+	return errors.Join(errs...)
 }
 
+/*
+[HandleUpdate] runs the middlewares and handlers registered for updateType with update.
+
+Deprecated: use [Bot.HandleEvent], which returns the error of the handlers.
+*/
 func (bot *Bot) HandleUpdate(updateType UpdateType, update Event) {
+	bot.HandleEvent(updateType, update)
+}
+
+// HandleEvent runs the middlewares and handlers registered for updateType with update.
+func (bot *Bot) HandleEvent(updateType UpdateType, update Event) error {
 	next := func(bot *Bot, event Event) error {
 		return bot.runHandlers(bot.handlers, updateType, event)
 	}
 
 	err := bot.runMiddlewares(bot.Middlewares, update, next)
-	if err != nil && bot.Config.OnErrorFunc != nil {
-		bot.Config.OnErrorFunc(bot, fmt.Errorf("handling update: %w", err))
+	if err == nil {
+		return nil
 	}
+
+	err = fmt.Errorf("handling update: %w", err)
+
+	if bot.Config.OnErrorFunc != nil {
+		bot.Config.OnErrorFunc(bot, err)
+	}
+
+	return err
 }
 
 func (bot *Bot) runHandlers(handlers Handlers, updateType UpdateType, event Event) error {
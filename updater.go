@@ -0,0 +1,83 @@
+package aquagram
+
+import (
+	"context"
+	"errors"
+)
+
+/*
+[UpdateSink] receives the updates of an [Updater].
+
+done is called once the handlers of update finished or update was dropped,
+it may be nil. The returned error reports why update was dropped,
+e.g. [ErrQueueFull].
+*/
+type UpdateSink func(update *Update, done func()) error
+
+/*
+[Updater] is a source of updates, such as [PollingUpdater] or [WebhookUpdater].
+
+Run passes every update to sink until ctx is done or the source is exhausted.
+*/
+type Updater interface {
+	Run(ctx context.Context, sink UpdateSink) error
+}
+
+/*
+[StartWithUpdater] starts the bot receiving updates from updater,
+it blocks until the updater stops.
+
+Updates are passed to the [Dispatcher] of the bot, see [Config.Dispatcher].
+*/
+func (bot *Bot) StartWithUpdater(updater Updater) error {
+	if err := bot.start(); err != nil {
+		return err
+	}
+
+	return bot.runUpdater(updater)
+}
+
+func (bot *Bot) runUpdater(updater Updater) error {
+	// the bot was shut down before the updater started
+	if !bot.add(&bot.updaters) {
+		return nil
+	}
+
+	defer bot.updaters.Done()
+
+	err := updater.Run(bot.updaterContext, bot.dispatch)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+// ChannelUpdater receives the updates sent to a channel, e.g. by another goroutine.
+type ChannelUpdater struct {
+	Updates <-chan *Update
+}
+
+func NewChannelUpdater(updates <-chan *Update) *ChannelUpdater {
+	updater := new(ChannelUpdater)
+	updater.Updates = updates
+
+	return updater
+}
+
+// Run returns when the channel is closed.
+func (updater *ChannelUpdater) Run(ctx context.Context, sink UpdateSink) error {
+	for {
+		select {
+		case update, ok := <-updater.Updates:
+			if !ok {
+				return nil
+			}
+
+			sink(update, nil)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
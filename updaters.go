@@ -2,9 +2,13 @@ package aquagram
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -15,8 +19,32 @@ type PollingOptions struct {
 	TimeoutRaw         int64         `json:"timeout,omitempty"`
 	AllowedUpdates     []string      `json:"allowed_updates,omitempty"`
 	DropPendingUpdates bool          `json:"-"`
+
+	// Store used to resume from the last handled update after a restart.
+	//
+	// When set, an update is confirmed to Telegram only once its handlers finished,
+	// so the updates being handled during a crash are received again.
+	OffsetStore OffsetStore `json:"-"`
+
+	// Time an update being handled holds back the offset, used with OffsetStore.
+	//
+	// Telegram returns at most Limit updates from the offset, so a slow handler
+	// delays the updates received after it once Limit of them are pending.
+	// After PendingTimeout the update is confirmed anyway, and it is not
+	// received again if the bot crashes before its handlers finish.
+	// A negative PendingTimeout always waits for the handlers.
+	//
+	// By default is [DefaultPendingTimeout]
+	PendingTimeout time.Duration `json:"-"`
 }
 
+// DefaultPendingTimeout is the default [PollingOptions.PendingTimeout].
+const DefaultPendingTimeout = time.Minute
+
+// pendingInterval is the time waited for the pending updates
+// when all the updates received are already being handled.
+const pendingInterval = 250 * time.Millisecond
+
 type Updates struct {
 	Result []*Update `json:"result"`
 }
@@ -24,6 +52,10 @@ type Updates struct {
 type PollingUpdater struct {
 	Bot     *Bot
 	Options *PollingOptions
+
+	// used with Options.OffsetStore
+	tracker     *offsetTracker
+	savedOffset int
 }
 
 func NewPollingUpdater(bot *Bot) *PollingUpdater {
@@ -34,6 +66,13 @@ func NewPollingUpdater(bot *Bot) *PollingUpdater {
 }
 
 func (updater *PollingUpdater) Start() {
+	updater.Bot.runUpdater(updater)
+}
+
+// Run gets the updates with long polling, it implements [Updater].
+func (updater *PollingUpdater) Run(ctx context.Context, sink UpdateSink) error {
+	bot := updater.Bot
+
 	if updater.Options == nil {
 		updater.Options = new(PollingOptions)
 	}
@@ -42,15 +81,26 @@ func (updater *PollingUpdater) Start() {
 		updater.Options.Timeout = 10 * time.Second
 	}
 
+	if updater.Options.OffsetStore != nil {
+		offset, err := updater.Options.OffsetStore.Load()
+		if err != nil && bot.Config.OnErrorFunc != nil {
+			bot.Config.OnErrorFunc(bot, fmt.Errorf("%w: loading offset: %w", ErrUpdaterError, err))
+		}
+
+		if offset > updater.Options.Offset {
+			updater.Options.Offset = offset
+		}
+	}
+
 	if updater.Options.DropPendingUpdates {
 		params := &PollingOptions{
 			Offset: -1,
 			Limit:  1,
 		}
 
-		updates, err := updater.Bot.GetUpdates(updater.Bot.stopContext, params)
-		if err != nil && updater.Bot.Config.OnErrorFunc != nil {
-			updater.Bot.Config.OnErrorFunc(updater.Bot, fmt.Errorf("%w: %w", ErrUpdaterError, err))
+		updates, err := bot.GetUpdates(ctx, params)
+		if err != nil && bot.Config.OnErrorFunc != nil {
+			bot.Config.OnErrorFunc(bot, fmt.Errorf("%w: %w", ErrUpdaterError, err))
 
 		} else {
 			if len(updates) > 0 {
@@ -60,26 +110,124 @@ func (updater *PollingUpdater) Start() {
 		}
 	}
 
+	if updater.Options.PendingTimeout == 0 {
+		updater.Options.PendingTimeout = DefaultPendingTimeout
+	}
+
+	if updater.Options.OffsetStore != nil {
+		updater.tracker = newOffsetTracker(updater.Options.Offset, updater.Options.PendingTimeout)
+		updater.savedOffset = updater.Options.Offset
+	}
+
 	for {
-		updates, err := updater.Bot.GetUpdates(updater.Bot.stopContext, updater.Options)
-		if err == context.Canceled {
-			updater.Bot.Config.Logger.Println("bot manually stopped")
-			break
+		if updater.tracker != nil {
+			updater.Options.Offset = updater.tracker.offset()
+			updater.saveOffset()
+		}
+
+		updates, err := bot.GetUpdates(ctx, updater.Options)
+		if errors.Is(err, context.Canceled) {
+			if updater.tracker != nil {
+				updater.tracker.drain(bot.stopContext)
+				updater.Options.Offset = updater.tracker.offset()
+				updater.saveOffset()
+			}
+
+			bot.Config.Logger.Info("polling stopped", slog.Int("offset", updater.Options.Offset))
+			updater.commitOffset()
+
+			return nil
 		}
 
 		if err != nil {
-			updater.Bot.Config.Logger.Println(fmt.Errorf("%w: %w", ErrUpdaterError, err))
-			time.Sleep(updater.Bot.Config.RetriesInterval)
+			bot.Config.Logger.Error("getting updates",
+				slog.Any("error", fmt.Errorf("%w: %w", ErrUpdaterError, err)),
+				slog.Duration("retry_in", bot.Config.RetriesInterval),
+			)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(bot.Config.RetriesInterval):
+			}
+
 			continue
 		}
 
+		if updater.tracker == nil {
+			for _, update := range updates {
+				updater.Options.Offset = update.UpdateID + 1
+				sink(update, nil)
+			}
+
+			continue
+		}
+
+		received := false
+
 		for _, update := range updates {
-			updater.Options.Offset = update.UpdateID + 1
-			go updater.Bot.DispatchUpdate(update)
+			// already received, its handlers did not finish yet
+			if !updater.tracker.add(update.UpdateID) {
+				continue
+			}
+
+			received = true
+
+			id := update.UpdateID
+			err := sink(update, func() {
+				updater.tracker.done(id)
+			})
+
+			// the bot or its dispatcher was stopped before the update was handled
+			if errors.Is(err, context.Canceled) || errors.Is(err, ErrDispatcherClosed) {
+				updater.tracker.abandon(id)
+			}
+		}
+
+		// getUpdates returns the pending updates without waiting for new ones,
+		// give their handlers some time before asking again
+		if len(updates) > 0 && !received {
+			updater.tracker.wait(ctx, updater.Options.Offset, pendingInterval)
 		}
 	}
 }
 
+func (updater *PollingUpdater) saveOffset() {
+	bot := updater.Bot
+
+	if updater.Options.Offset == updater.savedOffset {
+		return
+	}
+
+	if err := updater.Options.OffsetStore.Save(updater.Options.Offset); err != nil {
+		if bot.Config.OnErrorFunc != nil {
+			bot.Config.OnErrorFunc(bot, fmt.Errorf("%w: saving offset: %w", ErrUpdaterError, err))
+		}
+
+		return
+	}
+
+	updater.savedOffset = updater.Options.Offset
+}
+
+// commitOffset confirms the received updates to Telegram,
+// so they are not sent again on the next start.
+func (updater *PollingUpdater) commitOffset() {
+	bot := updater.Bot
+
+	if updater.Options.Offset == 0 || bot.stopContext.Err() != nil {
+		return
+	}
+
+	params := &PollingOptions{
+		Offset: updater.Options.Offset,
+		Limit:  1,
+	}
+
+	if _, err := bot.GetUpdates(bot.stopContext, params); err != nil && bot.Config.OnErrorFunc != nil {
+		bot.Config.OnErrorFunc(bot, fmt.Errorf("%w: %w", ErrUpdaterError, err))
+	}
+}
+
 func (bot *Bot) GetUpdates(ctx context.Context, params *PollingOptions) ([]*Update, error) {
 	params.TimeoutRaw = int64(params.Timeout.Seconds())
 
@@ -88,18 +236,69 @@ func (bot *Bot) GetUpdates(ctx context.Context, params *PollingOptions) ([]*Upda
 		return nil, err
 	}
 
-	updates := new(Updates)
+	return ParseRawResult[[]*Update](bot, data)
+}
 
-	if err := json.Unmarshal(data, updates); err != nil {
-		return nil, err
-	}
+type WebhookOptions struct {
+	// Address listened by the server, e.g. ":8443"
+	Addr string
+
+	// Public URL of the server, e.g. "https://example.com", Path is appended to it.
+	//
+	// When set, the webhook is registered with SetWebhook on start
+	// and deleted with DeleteWebhook on shutdown.
+	URL string
+
+	// Path receiving the updates, by default is "/"
+	Path string
+
+	// Token expected in the X-Telegram-Bot-Api-Secret-Token header, 1-256 characters.
+	SecretToken string
+
+	AllowedUpdates     []UpdateType
+	MaxConnections     int
+	IPAddress          string
+	DropPendingUpdates bool
 
-	return updates.Result, nil
+	// Certificate and key used to serve HTTPS,
+	// by default the server uses plain HTTP, e.g. behind a reverse proxy.
+	CertFile string
+	KeyFile  string
+
+	// Send CertFile to Telegram with SetWebhook, required for self-signed certificates.
+	UploadCertificate bool
+
+	// Max size of the request body, larger updates are refused.
+	//
+	// By default is 1MB
+	MaxBodySize int64
+
+	// Time the response to Telegram waits for the handlers
+	// to make a request with [Bot.WebhookReply].
+	//
+	// By default is 0, the response is sent immediately.
+	ReplyTimeout time.Duration
 }
 
+/*
+[WebhookUpdater] receives the updates sent by Telegram to a webhook.
+
+It can be mounted in an existing server without calling Start or Run,
+the updates are passed to the [Dispatcher] of the bot:
+
+	updater := aquagram.NewWebhookUpdater(bot)
+	updater.Options = &aquagram.WebhookOptions{SecretToken: secret}
+
+	router.Handle("/tg/mybot", updater)
+
+Updates that can not be queued, e.g. with [ErrQueueFull], are answered
+with 503 Service Unavailable so Telegram delivers them again.
+*/
 type WebhookUpdater struct {
-	Bot         *Bot
-	secretToken string
+	Bot     *Bot
+	Options *WebhookOptions
+
+	sink UpdateSink
 }
 
 func NewWebhookUpdater(bot *Bot) *WebhookUpdater {
@@ -110,33 +309,194 @@ func NewWebhookUpdater(bot *Bot) *WebhookUpdater {
 }
 
 func (updater *WebhookUpdater) Start(addr string) error {
+	if updater.Options == nil {
+		updater.Options = new(WebhookOptions)
+	}
+
+	updater.Options.Addr = addr
+
+	return updater.Bot.runUpdater(updater)
+}
+
+// Run serves the webhook on Options.Addr, it implements [Updater].
+func (updater *WebhookUpdater) Run(ctx context.Context, sink UpdateSink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if updater.Options == nil {
+		updater.Options = new(WebhookOptions)
+	}
+
+	options := updater.Options
+	bot := updater.Bot
+
+	path := options.Path
+	if path == EmptyString {
+		path = "/"
+	}
+
+	if options.URL != EmptyString {
+		if err := updater.setWebhook(ctx, strings.TrimSuffix(options.URL, "/")+path); err != nil {
+			return err
+		}
+	}
+
+	updater.sink = sink
+
 	router := http.NewServeMux()
-	router.HandleFunc("/", updater.Handler)
+	router.Handle(path, updater)
+
+	server := &http.Server{
+		Addr:    options.Addr,
+		Handler: router,
+	}
+
+	shutdown := make(chan error, 1)
+
+	stop := context.AfterFunc(ctx, func() {
+		var errs []error
+
+		if options.URL != EmptyString {
+			if err := bot.DeleteWebhookWithContext(bot.stopContext, false); err != nil {
+				errs = append(errs, fmt.Errorf("%w: deleting webhook: %w", ErrUpdaterError, err))
+			}
+		}
+
+		errs = append(errs, server.Shutdown(context.Background()))
+		shutdown <- errors.Join(errs...)
+	})
+
+	defer stop()
+
+	var err error
+
+	if options.CertFile != EmptyString {
+		err = server.ListenAndServeTLS(options.CertFile, options.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return <-shutdown
+	}
+
+	return err
+}
+
+func (updater *WebhookUpdater) setWebhook(ctx context.Context, url string) error {
+	options := updater.Options
+
+	params := &SetWebhookParams{
+		IPAddress:          options.IPAddress,
+		MaxConnections:     options.MaxConnections,
+		AllowedUpdates:     options.AllowedUpdates,
+		DropPendingUpdates: options.DropPendingUpdates,
+		SecretToken:        options.SecretToken,
+	}
 
-	return http.ListenAndServe(addr, router)
+	if options.UploadCertificate {
+		params.Certificate = InputFileFromPath(options.CertFile)
+	}
+
+	return updater.Bot.SetWebhookWithContext(ctx, url, params)
 }
 
+// Handler is the same as [WebhookUpdater.ServeHTTP].
 func (updater *WebhookUpdater) Handler(w http.ResponseWriter, r *http.Request) {
-	if updater.secretToken != EmptyString {
+	updater.ServeHTTP(w, r)
+}
+
+func (updater *WebhookUpdater) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	options := updater.Options
+	if options == nil {
+		options = new(WebhookOptions)
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if options.SecretToken != EmptyString {
 		secretToken := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
 
-		if secretToken != updater.secretToken {
+		if subtle.ConstantTimeCompare([]byte(secretToken), []byte(options.SecretToken)) != 1 {
+			updater.Bot.updateDropped(DropReasonUnauthorized)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
 	}
 
+	maxBodySize := options.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = 1 << 20
+	}
+
 	update := new(Update)
-	decoder := json.NewDecoder(r.Body)
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
 
 	err := decoder.Decode(update)
-	if err != nil && updater.Bot.Config.OnErrorFunc != nil {
-		updater.Bot.Config.OnErrorFunc(updater.Bot, fmt.Errorf("%w: %w", ErrUpdaterError, err))
+	if err != nil {
+		updater.Bot.updateDropped(DropReasonInvalid)
+
+		if updater.Bot.Config.OnErrorFunc != nil {
+			updater.Bot.Config.OnErrorFunc(updater.Bot, fmt.Errorf("%w: %w", ErrUpdaterError, err))
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	if update.UpdateID <= updater.Bot.LastUpdateID {
+	sink := updater.sink
+	if sink == nil {
+		sink = updater.Bot.dispatch
+	}
+
+	event := update.event()
+
+	if options.ReplyTimeout <= 0 || event == nil {
+		if err := sink(update, nil); err != nil {
+			updater.writeSinkError(w, err)
+		}
+
+		return
+	}
+
+	reply := newWebhookReply()
+
+	updater.Bot.webhookReplies.Store(event, reply)
+	defer updater.Bot.webhookReplies.Delete(event)
+
+	if err := sink(update, reply.done); err != nil {
+		updater.writeSinkError(w, err)
+		return
+	}
+
+	reply.wait(r.Context(), options.ReplyTimeout)
+
+	if err := reply.write(w); err != nil && updater.Bot.Config.OnErrorFunc != nil {
+		updater.Bot.Config.OnErrorFunc(updater.Bot, fmt.Errorf("%w: webhook reply: %w", ErrUpdaterError, err))
+	}
+}
+
+/*
+writeSinkError answers a webhook request whose update was not queued,
+so Telegram delivers it again later.
+
+Duplicate updates are answered with 200, they were already received.
+*/
+func (updater *WebhookUpdater) writeSinkError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrDuplicateUpdate) {
 		return
 	}
 
-	go updater.Bot.DispatchUpdate(update)
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 }
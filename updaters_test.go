@@ -0,0 +1,452 @@
+package aquagram_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aquagram/aquagram"
+	"github.com/aquagram/aquagram/aquagramtest"
+)
+
+func TestShutdown(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	started := make(chan struct{})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := message.Reply("done", nil)
+		return err
+	})
+
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- bot.StartPolling(false)
+	}()
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+
+	if _, err := server.InjectMessage(user, aquagramtest.PrivateChat(user), "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := bot.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+
+	if messages := server.SentMessages(user.ID); len(messages) != 1 {
+		t.Errorf("the handler was interrupted, sent %d messages", len(messages))
+	}
+
+	if request := server.LastRequest("getUpdates"); request.Int("limit") != 1 || request.Int("timeout") != 0 {
+		t.Errorf("the offset was not committed, last request %+v", request.Params)
+	}
+
+	if updates := server.PendingUpdates(); len(updates) != 0 {
+		t.Errorf("%d updates are still pending", len(updates))
+	}
+
+	if bot.Context().Err() == nil {
+		t.Error("the bot context was not cancelled")
+	}
+}
+
+func TestShutdownRacingStart(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	for i := 0; i < 20; i++ {
+		bot := server.NewBot()
+		bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+			return nil
+		})
+
+		go bot.StartPolling(false)
+
+		// e.g. webhook requests arriving while the bot shuts down
+		dispatched := make(chan struct{})
+
+		go func() {
+			defer close(dispatched)
+
+			dispatcher := aquagram.NewDispatcher(nil)
+			for id := 1; id <= 50; id++ {
+				dispatcher.Dispatch(bot, messageUpdate(id))
+			}
+		}()
+
+		if err := bot.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		<-dispatched
+
+		if err := bot.StartPolling(false); err == nil {
+			t.Error("the bot started after shutdown")
+		}
+	}
+}
+
+func TestChannelUpdater(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+
+	var mu sync.Mutex
+	var texts []string
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		texts = append(texts, message.Text)
+		return nil
+	})
+
+	updates := make(chan *aquagram.Update, 3)
+
+	for id, text := range []string{"a", "b", "c"} {
+		updates <- &aquagram.Update{
+			UpdateID: id + 1,
+			Message:  &aquagram.Message{Chat: &aquagram.Chat{ID: 42}, Text: text},
+		}
+	}
+
+	close(updates)
+
+	if err := bot.StartWithUpdater(aquagram.NewChannelUpdater(updates)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(texts) != 3 {
+		t.Errorf("unexpected handled messages %v", texts)
+	}
+}
+
+func TestWebhookLifecycle(t *testing.T) {
+	server := aquagramtest.NewServer()
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	listener.Close()
+
+	bot := server.NewBot()
+
+	handled := make(chan string, 1)
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		handled <- message.Text
+		return nil
+	})
+
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- bot.StartWebhookWithOptions(&aquagram.WebhookOptions{
+			Addr:        addr,
+			URL:         "http://" + addr,
+			Path:        "/telegram",
+			SecretToken: "secret",
+		})
+	}()
+
+	user := &aquagram.User{ID: 42, FirstName: "John"}
+	message := server.UserMessage(user, aquagramtest.PrivateChat(user), "hello")
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	// wait for setWebhook, then for the server to listen
+	for server.Webhook() == nil || server.PushUpdate(&aquagram.Update{Message: message}) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("the webhook was not started")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if text := <-handled; text != "hello" {
+		t.Errorf("unexpected message %q", text)
+	}
+
+	webhook := server.Webhook()
+	if webhook.URL != "http://"+addr+"/telegram" || webhook.SecretToken != "secret" {
+		t.Errorf("unexpected webhook %+v", webhook)
+	}
+
+	res, err := http.Post(webhook.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", res.StatusCode)
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, webhook.URL, strings.NewReader("{"))
+	request.Header.Set("X-Telegram-Bot-Api-Secret-Token", "secret")
+
+	res, err = http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", res.StatusCode)
+	}
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Webhook() != nil {
+		t.Error("the webhook was not deleted")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	bot := aquagram.NewBot("token")
+
+	handled := make(chan string, 1)
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		handled <- message.Text
+		return nil
+	})
+
+	updater := aquagram.NewWebhookUpdater(bot)
+	updater.Options = &aquagram.WebhookOptions{
+		SecretToken: "secret",
+		MaxBodySize: 256,
+	}
+
+	router := http.NewServeMux()
+	router.Handle("/tg/bot", updater)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		secret string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "secret", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "wrong", "{}", http.StatusUnauthorized},
+		{http.MethodPost, "secret", "{", http.StatusBadRequest},
+		{http.MethodPost, "secret", `{"update_id":1,"message":{"text":"` + strings.Repeat("a", 300) + `"}}`, http.StatusRequestEntityTooLarge},
+		{http.MethodPost, "secret", `{"update_id":2,"message":{"message_id":1,"chat":{"id":42},"text":"hello"}}`, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		request, _ := http.NewRequest(test.method, server.URL+"/tg/bot", strings.NewReader(test.body))
+		request.Header.Set("X-Telegram-Bot-Api-Secret-Token", test.secret)
+
+		res, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		if res.StatusCode != test.status {
+			t.Errorf("%s %q: expected status %d, got %d", test.method, test.secret, test.status, res.StatusCode)
+		}
+	}
+
+	if text := <-handled; text != "hello" {
+		t.Errorf("unexpected message %q", text)
+	}
+
+	bot.Shutdown(context.Background())
+}
+
+func TestWebhookReply(t *testing.T) {
+	transport := new(recordingTransport)
+
+	limiter := new(countingLimiter)
+	metrics := &callMetrics{calls: make(map[string]int)}
+
+	var hooks atomic.Int32
+
+	bot := aquagram.NewBot("token")
+	bot.Config.Transport = transport
+	bot.Config.Limiter = limiter
+	bot.Config.Metrics = metrics
+	bot.Config.OnRequestFuncs = append(bot.Config.OnRequestFuncs, func(bot *aquagram.Bot, request *aquagram.APIRequest) error {
+		hooks.Add(1)
+		return nil
+	})
+
+	bot.OnMessage(func(bot *aquagram.Bot, message *aquagram.Message) error {
+		if err := message.ReplyViaWebhook("first", nil); err != nil {
+			return err
+		}
+
+		// the webhook reply is already used
+		return message.ReplyViaWebhook("second", nil)
+	})
+
+	updater := aquagram.NewWebhookUpdater(bot)
+	updater.Options = &aquagram.WebhookOptions{ReplyTimeout: time.Second}
+
+	server := httptest.NewServer(updater)
+	defer server.Close()
+
+	body := `{"update_id":1,"message":{"message_id":7,"chat":{"id":42},"text":"hello"}}`
+
+	res, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	var reply map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply["method"] != "sendMessage" || reply["text"] != "first" || reply["chat_id"] != "42" {
+		t.Errorf("unexpected reply %v", reply)
+	}
+
+	bot.Shutdown(context.Background())
+
+	if len(transport.requests) != 1 || transport.requests[0].Get("text") != "second" {
+		t.Errorf("unexpected requests %+v", transport.requests)
+	}
+
+	// the hooks and the limiter are applied once per request
+	if count := hooks.Load(); count != 2 {
+		t.Errorf("request hooks called %d times", count)
+	}
+
+	if count := limiter.count.Load(); count != 2 {
+		t.Errorf("limiter called %d times", count)
+	}
+
+	if count := metrics.count("sendMessage"); count != 2 {
+		t.Errorf("%d sendMessage calls reported", count)
+	}
+}
+
+type countingLimiter struct {
+	count atomic.Int32
+}
+
+func (limiter *countingLimiter) Wait(ctx context.Context, method string, chatID string) error {
+	limiter.count.Add(1)
+	return nil
+}
+
+type callMetrics struct {
+	aquagram.Metrics
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (metrics *callMetrics) APICall(method string, outcome string, duration time.Duration) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.calls[method]++
+}
+
+func (metrics *callMetrics) UpdateReceived(updateType aquagram.UpdateType) {}
+
+func (metrics *callMetrics) UpdateHandled(updateType aquagram.UpdateType, duration time.Duration, err error) {
+}
+
+func (metrics *callMetrics) count(method string) int {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	return metrics.calls[method]
+}
+
+func TestWebhookQueueFull(t *testing.T) {
+	bot, _, handled := newDispatcherBot(t, aquagram.QueueReject)
+
+	server := httptest.NewServer(aquagram.NewWebhookUpdater(bot))
+	defer server.Close()
+
+	// taken by the worker
+	if status := postUpdate(t, server.URL, 1); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	<-handled
+
+	// waiting in the queue
+	if status := postUpdate(t, server.URL, 2); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	// not queued, Telegram must deliver it again
+	if status := postUpdate(t, server.URL, 3); status != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", status)
+	}
+}
+
+// postUpdate posts a message update to a webhook and returns the status of the response.
+func postUpdate(t *testing.T, url string, id int) int {
+	t.Helper()
+
+	body, err := json.Marshal(messageUpdate(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.Body.Close()
+
+	return res.StatusCode
+}
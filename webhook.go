@@ -1,6 +1,7 @@
 package aquagram
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 )
@@ -29,12 +30,19 @@ type SetWebhookParams struct {
 
 // https://core.telegram.org/bots/api#setwebhook
 func (bot *Bot) SetWebhook(url string, params *SetWebhookParams) error {
+	return bot.SetWebhookWithContext(bot.stopContext, url, params)
+}
+
+func (bot *Bot) SetWebhookWithContext(ctx context.Context, url string, params *SetWebhookParams) error {
 	if params == nil {
 		params = new(SetWebhookParams)
 	}
 
 	params.URL = url
 
+	var data []byte
+	var err error
+
 	if params.Certificate != nil {
 		paramsMap, err := params.ToParams()
 		if err != nil {
@@ -44,24 +52,16 @@ func (bot *Bot) SetWebhook(url string, params *SetWebhookParams) error {
 		files := make(Files)
 		files["certificate"] = params.Certificate
 
-		data, err := bot.RawFile(bot.stopContext, "setWebhook", paramsMap, files)
+		data, err = bot.RawFile(ctx, "setWebhook", paramsMap, files)
 		if err != nil {
 			return err
 		}
 
-		success, err := ParseRawResult[bool](bot, data)
+	} else {
+		data, err = bot.Raw(ctx, "setWebhook", params)
 		if err != nil {
 			return err
 		}
-
-		if !success {
-			return ErrExpectedTrue
-		}
-	}
-
-	data, err := bot.Raw(bot.stopContext, "setWebhook", params)
-	if err != nil {
-		return err
 	}
 
 	success, err := ParseRawResult[bool](bot, data)
@@ -78,6 +78,10 @@ func (bot *Bot) SetWebhook(url string, params *SetWebhookParams) error {
 
 // https://core.telegram.org/bots/api#deletewebhook
 func (bot *Bot) DeleteWebhook(dropPendingUpdates bool) error {
+	return bot.DeleteWebhookWithContext(bot.stopContext, dropPendingUpdates)
+}
+
+func (bot *Bot) DeleteWebhookWithContext(ctx context.Context, dropPendingUpdates bool) error {
 	var params Params
 
 	if dropPendingUpdates {
@@ -85,7 +89,7 @@ func (bot *Bot) DeleteWebhook(dropPendingUpdates bool) error {
 		params["drop_pending_updates"] = TrueAsString
 	}
 
-	data, err := bot.Raw(bot.stopContext, "deleteWebhook", params)
+	data, err := bot.Raw(ctx, "deleteWebhook", params)
 	if err != nil {
 		return err
 	}
@@ -104,7 +108,11 @@ func (bot *Bot) DeleteWebhook(dropPendingUpdates bool) error {
 
 // https://core.telegram.org/bots/api#getwebhookinfo
 func (bot *Bot) GetWebhookInfo() (*WebhookInfo, error) {
-	data, err := bot.Raw(bot.stopContext, "getWebhookInfo", nil)
+	return bot.GetWebhookInfoWithContext(bot.stopContext)
+}
+
+func (bot *Bot) GetWebhookInfoWithContext(ctx context.Context) (*WebhookInfo, error) {
+	data, err := bot.Raw(ctx, "getWebhookInfo", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +122,7 @@ func (bot *Bot) GetWebhookInfo() (*WebhookInfo, error) {
 
 func (p *SetWebhookParams) ToParams() (Params, error) {
 	params := make(Params)
+	params["url"] = p.URL
 
 	if p.IPAddress != EmptyString {
 		params["ip_address"] = p.IPAddress
@@ -0,0 +1,162 @@
+package aquagram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// webhookReply is the response of the webhook request of an update.
+type webhookReply struct {
+	mu      sync.Mutex
+	request *APIRequest
+	closed  bool
+
+	// closed when request is set
+	ready chan struct{}
+
+	// closed when the update is handled or dropped
+	handled chan struct{}
+}
+
+func newWebhookReply() *webhookReply {
+	reply := new(webhookReply)
+	reply.ready = make(chan struct{})
+	reply.handled = make(chan struct{})
+
+	return reply
+}
+
+func (reply *webhookReply) done() {
+	close(reply.handled)
+}
+
+// set uses request as reply, it returns false if the reply was already sent or set.
+func (reply *webhookReply) set(request *APIRequest) bool {
+	reply.mu.Lock()
+	defer reply.mu.Unlock()
+
+	if reply.closed || reply.request != nil {
+		return false
+	}
+
+	reply.request = request
+	close(reply.ready)
+
+	return true
+}
+
+// wait returns once the reply is set, the update is handled or timeout elapsed.
+func (reply *webhookReply) wait(ctx context.Context, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-reply.ready:
+	case <-reply.handled:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// write answers the webhook request with the reply, if any.
+func (reply *webhookReply) write(w http.ResponseWriter) error {
+	reply.mu.Lock()
+	reply.closed = true
+	request := reply.request
+	reply.mu.Unlock()
+
+	if request == nil {
+		return nil
+	}
+
+	params, err := request.paramsMap()
+	if err != nil {
+		return err
+	}
+
+	params["method"] = request.Method
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(params)
+}
+
+/*
+[WebhookReply] performs an API request in the response to the webhook request
+that delivered event, saving a round trip. It can be used once per update,
+see [WebhookOptions.ReplyTimeout].
+
+The result of the request is not known, so it is meant for calls like
+sendMessage or answerCallbackQuery whose result is not needed.
+
+If event was not received by a webhook, the reply was already used,
+or the response was already sent, the request is performed as usual.
+Either way [Config.OnRequestFuncs] and the [Limiter] are applied once,
+and the request is reported to [Config.Metrics]; a reply is reported
+as successful since its result is not known.
+*/
+func (bot *Bot) WebhookReply(event Event, method string, params any) error {
+	value, ok := bot.webhookReplies.Load(event)
+	if !ok {
+		data, err := bot.Raw(bot.stopContext, method, params)
+		if err != nil {
+			return err
+		}
+
+		return decodeError(data)
+	}
+
+	request := &APIRequest{
+		Method: method,
+		Params: params,
+	}
+
+	for _, fn := range bot.Config.OnRequestFuncs {
+		if err := fn(bot, request); err != nil {
+			return err
+		}
+	}
+
+	if err := bot.wait(bot.stopContext, request); err != nil {
+		return err
+	}
+
+	if value.(*webhookReply).set(request) {
+		if bot.Config.Metrics != nil {
+			bot.Config.Metrics.APICall(request.Method, OutcomeOK, 0)
+		}
+
+		return nil
+	}
+
+	// the hooks and the limiter were already applied
+	data, err := bot.exchange(bot.stopContext, request)
+	if err != nil {
+		return err
+	}
+
+	return decodeError(data)
+}
+
+/*
+[ReplyViaWebhook] is like [Message.Reply], but the message is sent
+in the response to the webhook request, see [Bot.WebhookReply].
+*/
+func (message *Message) ReplyViaWebhook(text string, params *SendMessageParams) error {
+	if params == nil {
+		params = new(SendMessageParams)
+	}
+
+	if params.ReplyParameters == nil {
+		params.ReplyParameters = new(ReplyParameters)
+	}
+
+	params.ReplyParameters.MessageID = message.MessageID
+	params.ChatID = ChatID(message.Chat.ID)
+	params.Text = text
+
+	return message.Bot.WebhookReply(message, "sendMessage", params)
+}